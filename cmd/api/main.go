@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"os"
+	"time"
+
 	"github.com/deepanshumishra/devcost-api/internal/api"
+	"github.com/deepanshumishra/devcost-api/internal/aws"
 	"github.com/deepanshumishra/devcost-api/internal/config"
 	"github.com/gin-gonic/gin"
 )
@@ -30,7 +34,28 @@ func main() {
 		os.Exit(1)
 	}
 
-	
+	// Ingest Cost and Usage Report data in the background
+	if cfg.CURDataDir != "" || cfg.CURS3Bucket != "" {
+		go func() {
+			if _, err := aws.IngestCUR(context.Background(), cfg); err != nil {
+				slog.Error("Initial CUR ingestion failed", "error", err)
+			}
+		}()
+	}
+
+	// Evaluate budgets hourly and send alerts for newly crossed thresholds
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if len(cfg.BudgetStore.List()) == 0 {
+				continue
+			}
+			if _, err := aws.EvaluateBudgets(context.Background(), cfg); err != nil {
+				slog.Error("Budget evaluation failed", "error", err)
+			}
+		}
+	}()
 
 	// Setup routes
 	api.SetupRoutes(r, cfg)
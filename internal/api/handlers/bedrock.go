@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// GetBedrockTokenUsage returns a handler function that breaks down Bedrock token usage and
+// estimated on-demand spend by model over start/end (default: the last 7 days).
+func GetBedrockTokenUsage(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, ok := parseDateRange(c)
+		if !ok {
+			return
+		}
+
+		usage, err := aws.ListBedrockTokenUsage(c.Request.Context(), cfg, start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		totalCost := 0.0
+		for _, modelUsage := range usage {
+			totalCost += modelUsage.EstimatedCost
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"models":               usage,
+			"total_estimated_cost": totalCost,
+			"start":                start.Format("2006-01-02"),
+			"end":                  end.Format("2006-01-02"),
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/budget"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListBudgets returns a handler function that lists all budgets.
+func ListBudgets(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"budgets": cfg.BudgetStore.List(),
+		})
+	}
+}
+
+// GetBudget returns a handler function that fetches a single budget.
+func GetBudget(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, err := cfg.BudgetStore.Get(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"budget": b,
+		})
+	}
+}
+
+// GetBudgetStatus returns a handler function that reports month-to-date and forecasted spend for a budget.
+func GetBudgetStatus(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, err := cfg.BudgetStore.Get(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		status, err := aws.GetBudgetStatus(c.Request.Context(), cfg, b, time.Now().UTC())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": status,
+		})
+	}
+}
+
+// CreateBudget returns a handler function that creates a budget.
+func CreateBudget(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b models.Budget
+		if err := c.ShouldBindJSON(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if err := budget.Validate(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		created, err := cfg.BudgetStore.Create(b)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"budget": created,
+		})
+	}
+}
+
+// UpdateBudget returns a handler function that replaces a budget's definition.
+func UpdateBudget(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b models.Budget
+		if err := c.ShouldBindJSON(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if err := budget.Validate(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := cfg.BudgetStore.Update(c.Param("id"), b)
+		if err != nil {
+			if errors.Is(err, budget.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"budget": updated,
+		})
+	}
+}
+
+// DeleteBudget returns a handler function that deletes a budget.
+func DeleteBudget(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := cfg.BudgetStore.Delete(c.Param("id")); err != nil {
+			if errors.Is(err, budget.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// EvaluateBudgets returns a handler function that checks all budgets now and sends any due alerts.
+func EvaluateBudgets(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		statuses, err := aws.EvaluateBudgets(c.Request.Context(), cfg)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":    err.Error(),
+				"statuses": statuses,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"statuses": statuses,
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/chargeback"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/currency"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetChargebackRules returns a handler function that returns the chargeback rules.
+func GetChargebackRules(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rules, ok := cfg.ChargebackStore.Rules()
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No chargeback rules configured, set them via PUT /chargeback/rules"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"rules": rules,
+		})
+	}
+}
+
+// UpdateChargebackRules returns a handler function that replaces the chargeback rules.
+func UpdateChargebackRules(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var rules models.ChargebackRules
+		if err := c.ShouldBindJSON(&rules); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if err := chargeback.Validate(rules); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := cfg.ChargebackStore.SetRules(rules); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"rules": rules,
+		})
+	}
+}
+
+// GetChargebackStatements returns a handler function that builds the month's statements for all cost centers.
+func GetChargebackStatements(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rules, ok := cfg.ChargebackStore.Rules()
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No chargeback rules configured, set them via PUT /chargeback/rules"})
+			return
+		}
+		month, ok := parseMonth(c)
+		if !ok {
+			return
+		}
+		targetCurrency, ok := parseCurrency(c, cfg)
+		if !ok {
+			return
+		}
+
+		statements, err := getChargebackStatements(c, cfg, rules, month, targetCurrency)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"statements": statements,
+		})
+	}
+}
+
+// GetChargebackStatement returns a handler function that renders one cost center's statement
+// as JSON, CSV or PDF.
+func GetChargebackStatement(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" && format != "pdf" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use json, csv or pdf"})
+			return
+		}
+		rules, ok := cfg.ChargebackStore.Rules()
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No chargeback rules configured, set them via PUT /chargeback/rules"})
+			return
+		}
+		month, ok := parseMonth(c)
+		if !ok {
+			return
+		}
+		targetCurrency, ok := parseCurrency(c, cfg)
+		if !ok {
+			return
+		}
+
+		statements, err := getChargebackStatements(c, cfg, rules, month, targetCurrency)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		costCenter := c.Param("cost_center")
+		for _, statement := range statements {
+			if statement.CostCenter != costCenter {
+				continue
+			}
+
+			filename := "chargeback-" + costCenter + "-" + statement.Month + "." + format
+			switch format {
+			case "csv":
+				c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+				c.Header("Content-Type", "text/csv")
+				c.Status(http.StatusOK)
+				if err := chargeback.WriteCSV(c.Writer, statement); err != nil {
+					c.Error(err)
+				}
+			case "pdf":
+				c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+				c.Header("Content-Type", "application/pdf")
+				c.Status(http.StatusOK)
+				if err := chargeback.WritePDF(c.Writer, statement); err != nil {
+					c.Error(err)
+				}
+			default:
+				c.JSON(http.StatusOK, gin.H{
+					"statement": statement,
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown cost center: " + costCenter})
+	}
+}
+
+// getChargebackStatements builds the month's statements, converting them to targetCurrency at
+// the rate for the month's last day when it is set.
+func getChargebackStatements(c *gin.Context, cfg *config.Config, rules models.ChargebackRules, month time.Time, targetCurrency string) ([]models.ChargebackStatement, error) {
+	statements, err := aws.GetChargebackStatements(c.Request.Context(), cfg, rules, month)
+	if err != nil || targetCurrency == "" {
+		return statements, err
+	}
+	lastDay := month.AddDate(0, 1, -1)
+	if err := currency.ConvertStatements(c.Request.Context(), cfg.CurrencyRates, statements, targetCurrency, lastDay); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// noCommitmentDataWarning is returned when Cost Explorer has no commitment data for the range,
+// typically because the account holds no Reserved Instances or Savings Plans.
+const noCommitmentDataWarning = "No commitment data available for this period"
+
+// GetReservationUtilization returns a handler function that reports Reserved Instance utilization.
+func GetReservationUtilization(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, granularity, ok := parseCommitmentParams(c)
+		if !ok {
+			return
+		}
+
+		periods, total, err := aws.GetReservationUtilization(c.Request.Context(), cfg, start, end, granularity)
+		if err != nil {
+			respondCommitmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"total":   total,
+			"periods": periods,
+		})
+	}
+}
+
+// GetSavingsPlansUtilization returns a handler function that reports Savings Plans utilization.
+func GetSavingsPlansUtilization(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, granularity, ok := parseCommitmentParams(c)
+		if !ok {
+			return
+		}
+
+		periods, total, err := aws.GetSavingsPlansUtilization(c.Request.Context(), cfg, start, end, granularity)
+		if err != nil {
+			respondCommitmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"total":   total,
+			"periods": periods,
+		})
+	}
+}
+
+// GetReservationCoverage returns a handler function that reports Reserved Instance coverage,
+// optionally for one service (e.g. ?service=Amazon Relational Database Service).
+func GetReservationCoverage(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, granularity, ok := parseCommitmentParams(c)
+		if !ok {
+			return
+		}
+
+		periods, total, err := aws.GetReservationCoverage(c.Request.Context(), cfg, start, end, granularity, c.Query("service"))
+		if err != nil {
+			respondCommitmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"total":   total,
+			"periods": periods,
+		})
+	}
+}
+
+// GetSavingsPlansCoverage returns a handler function that reports Savings Plans coverage by service.
+func GetSavingsPlansCoverage(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, granularity, ok := parseCommitmentParams(c)
+		if !ok {
+			return
+		}
+
+		periods, total, err := aws.GetSavingsPlansCoverage(c.Request.Context(), cfg, start, end, granularity)
+		if err != nil {
+			respondCommitmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"total":   total,
+			"periods": periods,
+		})
+	}
+}
+
+// parseCommitmentParams reads the date range and granularity (daily or monthly, default monthly).
+func parseCommitmentParams(c *gin.Context) (start, end time.Time, granularity types.Granularity, ok bool) {
+	start, end, ok = parseDateRange(c)
+	if !ok {
+		return start, end, granularity, false
+	}
+	switch c.DefaultQuery("granularity", "monthly") {
+	case "daily":
+		granularity = types.GranularityDaily
+	case "monthly":
+		granularity = types.GranularityMonthly
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid granularity, use daily or monthly"})
+		return start, end, granularity, false
+	}
+	return start, end, granularity, true
+}
+
+// respondCommitmentError reports an empty result when the account has no commitments and a 500 otherwise.
+func respondCommitmentError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "DataUnavailableException") {
+		c.JSON(http.StatusOK, gin.H{
+			"periods": []interface{}{},
+			"warning": noCommitmentDataWarning,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
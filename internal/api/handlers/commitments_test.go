@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCommitmentAndMetricValidation(t *testing.T) {
+	cfg := &config.Config{CostSource: "cost_explorer"}
+
+	r := gin.Default()
+	r.GET("/costs/tag", GetTagCosts(cfg))
+	r.GET("/commitments/savings-plans/utilization", GetSavingsPlansUtilization(cfg))
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"net amortized from CUR", "/costs/tag?tag_key=project&metric=net_amortized&source=cur"},
+		{"unknown metric", "/costs/tag?tag_key=project&metric=blended"},
+		{"invalid granularity", "/commitments/savings-plans/utilization?granularity=hourly"},
+		{"partial date range", "/commitments/savings-plans/utilization?start=2025-05-01"},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tt.name, w.Code)
+		}
+	}
+}
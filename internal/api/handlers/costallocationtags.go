@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCostAllocationTags returns a handler function that lists cost allocation tags with their status.
+func GetCostAllocationTags(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Optional comma-separated filter, e.g. ?tag_keys=project,team
+		var tagKeys []string
+		if tagKeysStr := c.Query("tag_keys"); tagKeysStr != "" {
+			for _, tagKey := range strings.Split(tagKeysStr, ",") {
+				if tagKey = strings.TrimSpace(tagKey); tagKey != "" {
+					tagKeys = append(tagKeys, tagKey)
+				}
+			}
+		}
+
+		tags, err := aws.ListCostAllocationTags(c.Request.Context(), cfg, tagKeys)
+		if err != nil {
+			if strings.Contains(err.Error(), "UnrecognizedClientException") {
+				mockTags := []models.CostAllocationTag{
+					{
+						TagKey:       "project",
+						Type:         "UserDefined",
+						Status:       "Active",
+						LastUsedDate: "2025-05-01T00:00:00Z",
+					},
+					{
+						TagKey: "aws:createdBy",
+						Type:   "AWSGenerated",
+						Status: "Inactive",
+					},
+				}
+				c.JSON(http.StatusOK, gin.H{
+					"cost_allocation_tags": mockTags,
+					"warning":              "Using mock data due to invalid AWS credentials",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"cost_allocation_tags": tags,
+		})
+	}
+}
+
+// UpdateCostAllocationTagsStatus returns a handler function that activates or deactivates cost allocation tags.
+func UpdateCostAllocationTagsStatus(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Tags []models.CostAllocationTagStatusUpdate `json:"tags"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if len(req.Tags) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tags must contain at least one entry"})
+			return
+		}
+		for _, tag := range req.Tags {
+			if tag.TagKey == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "tag_key is required for every entry"})
+				return
+			}
+			if tag.Status != "Active" && tag.Status != "Inactive" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "status must be Active or Inactive"})
+				return
+			}
+		}
+
+		updateErrors, err := aws.UpdateCostAllocationTagsStatus(c.Request.Context(), cfg, req.Tags)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		status := http.StatusOK
+		if len(updateErrors) > 0 {
+			status = http.StatusMultiStatus
+		}
+		c.JSON(status, gin.H{
+			"updated": len(req.Tags) - len(updateErrors),
+			"errors":  updateErrors,
+		})
+	}
+}
+
+// StartCostAllocationTagBackfill returns a handler function that requests a cost allocation tag backfill.
+func StartCostAllocationTagBackfill(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			BackfillFrom string `json:"backfill_from"` // e.g., "2025-01-01"
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		backfillFrom, err := time.Parse("2006-01-02", req.BackfillFrom)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid backfill_from date format, use YYYY-MM-DD"})
+			return
+		}
+		// Backfills start on the first day of the month, and AWS allows backfilling at most 12 months
+		backfillFrom = time.Date(backfillFrom.Year(), backfillFrom.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if backfillFrom.Before(time.Now().AddDate(-1, 0, 0)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "backfill_from must be within the last 12 months"})
+			return
+		}
+
+		backfill, err := aws.StartCostAllocationTagBackfill(c.Request.Context(), cfg, backfillFrom)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"backfill": backfill,
+		})
+	}
+}
+
+// GetCostAllocationTagBackfills returns a handler function that lists cost allocation tag backfill requests.
+func GetCostAllocationTagBackfills(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		backfills, err := aws.ListCostAllocationTagBackfillHistory(c.Request.Context(), cfg)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"backfills": backfills,
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/deepanshumishra/devcost-api/internal/api/middleware"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestUpdateCostAllocationTagsStatus(t *testing.T) {
+	cfg := &config.Config{APIKeys: map[string]string{"admin-key": "admin", "viewer-key": "viewer"}}
+
+	r := gin.Default()
+	r.PUT("/costs/allocation-tags/status", middleware.RequireRole(cfg, "admin"), UpdateCostAllocationTagsStatus(cfg))
+
+	tests := []struct {
+		name   string
+		apiKey string
+		body   string
+		want   int
+	}{
+		{"missing api key", "", `{"tags":[{"tag_key":"project","status":"Active"}]}`, http.StatusUnauthorized},
+		{"non-admin api key", "viewer-key", `{"tags":[{"tag_key":"project","status":"Active"}]}`, http.StatusForbidden},
+		{"empty tags", "admin-key", `{"tags":[]}`, http.StatusBadRequest},
+		{"invalid status", "admin-key", `{"tags":[{"tag_key":"project","status":"Enabled"}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("PUT", "/costs/allocation-tags/status", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.apiKey != "" {
+			req.Header.Set("X-API-Key", tt.apiKey)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+}
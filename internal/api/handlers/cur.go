@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// IngestCUR returns a handler function that ingests new Cost and Usage Report files.
+func IngestCUR(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Ingestion can outlast the request; a client disconnect must not abort it partway
+		results, err := aws.IngestCUR(context.WithoutCancel(c.Request.Context()), cfg)
+		if err != nil {
+			if errors.Is(err, aws.ErrCURIngestInProgress) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   err.Error(),
+				"results": results,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"results": results,
+			"store":   cfg.CURStore.Stats(),
+		})
+	}
+}
+
+// GetCURStatus returns a handler function that summarises the ingested CUR data.
+func GetCURStatus(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"store": cfg.CURStore.Stats(),
+		})
+	}
+}
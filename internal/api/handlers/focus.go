@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/focus"
+	"github.com/gin-gonic/gin"
+)
+
+// maxFOCUSUploadBytes caps the size of an imported FOCUS file.
+const maxFOCUSUploadBytes = 512 << 20
+
+// ExportCosts returns a handler function that exports costs in the FinOps FOCUS schema.
+func ExportCosts(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.Query("format")
+		output := c.DefaultQuery("output", "csv")
+		source := c.DefaultQuery("source", cfg.CostSource)
+
+		if format != "focus" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, only focus is supported"})
+			return
+		}
+		if output != "csv" && output != "json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid output, use csv or json"})
+			return
+		}
+		if source != "cost_explorer" && source != "cur" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source, use cost_explorer or cur"})
+			return
+		}
+		start, end, ok := parseDateRange(c)
+		if !ok {
+			return
+		}
+
+		rows, err := aws.ExportFOCUS(c.Request.Context(), cfg, source, start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if output == "json" {
+			c.JSON(http.StatusOK, gin.H{
+				"columns": focus.Columns,
+				"rows":    rows,
+			})
+			return
+		}
+
+		filename := "focus-" + start.Format("2006-01-02") + "-" + end.Format("2006-01-02") + ".csv"
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		if err := focus.WriteCSV(c.Writer, rows); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// ImportFOCUS returns a handler function that imports a FOCUS CSV or Parquet file from another cloud.
+func ImportFOCUS(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A FOCUS file is required in the file form field"})
+			return
+		}
+		if fileHeader.Size > maxFOCUSUploadBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "FOCUS file is too large"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file: " + err.Error()})
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file: " + err.Error()})
+			return
+		}
+
+		imported, err := aws.ImportFOCUS(cfg, fileHeader.Filename, data, c.Query("replace") == "true")
+		if err != nil {
+			if errors.Is(err, aws.ErrFOCUSImportExists) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"file":     fileHeader.Filename,
+			"imported": imported,
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOptimizations returns a handler function that lists in-use resources with a cheaper
+// configuration, such as gp2 volumes, previous-generation instances and S3 buckets suited to
+// Intelligent-Tiering.
+func GetOptimizations(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		optimizations, err := aws.ListOptimizations(c.Request.Context(), cfg)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		totalSavings := 0.0
+		for _, optimization := range optimizations {
+			totalSavings += optimization.EstimatedMonthlySavings
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"optimizations":                   optimizations,
+			"total_estimated_monthly_savings": totalSavings,
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// parseDateRange reads the start and end query parameters (YYYY-MM-DD), defaulting to the
+// last 7 days. On invalid input it writes a 400 response and returns ok=false.
+func parseDateRange(c *gin.Context) (start, end time.Time, ok bool) {
+	startStr := c.Query("start") // e.g., "2025-05-01"
+	endStr := c.Query("end")     // e.g., "2025-05-07"
+	var err error
+
+	if startStr != "" && endStr != "" {
+		start, err = time.Parse("2006-01-02", startStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format, use YYYY-MM-DD"})
+			return start, end, false
+		}
+		end, err = time.Parse("2006-01-02", endStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format, use YYYY-MM-DD"})
+			return start, end, false
+		}
+		if !end.After(start) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "End date must be after start date"})
+			return start, end, false
+		}
+	} else if startStr == "" && endStr == "" {
+		// Default: last 7 days
+		end = time.Now()
+		start = end.AddDate(0, 0, -7)
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Both start and end dates must be provided together"})
+		return start, end, false
+	}
+
+	return start, end, true
+}
+
+// parseMonth reads the month query parameter (YYYY-MM), defaulting to the previous calendar
+// month. On invalid input it writes a 400 response and returns ok=false.
+func parseMonth(c *gin.Context) (month time.Time, ok bool) {
+	monthStr := c.Query("month") // e.g., "2025-05"
+	if monthStr == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC), true
+	}
+	month, err := time.Parse("2006-01", monthStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month format, use YYYY-MM"})
+		return month, false
+	}
+	return month, true
+}
+
+// parseCurrency reads the optional currency query parameter (ISO 4217 code, e.g. EUR). On
+// invalid input, or when no exchange rates are configured, it writes a 400 response and returns ok=false.
+func parseCurrency(c *gin.Context, cfg *config.Config) (currency string, ok bool) {
+	currency = strings.ToUpper(c.Query("currency"))
+	if currency == "" {
+		return "", true
+	}
+	if len(currency) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid currency, use a three-letter ISO 4217 code"})
+		return "", false
+	}
+	if cfg.CurrencyRates == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Currency conversion is not configured, set CURRENCY_RATES_FILE or CURRENCY_RATES_URL"})
+		return "", false
+	}
+	return currency, true
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/aws"
+	"github.com/deepanshumishra/devcost-api/internal/commitment"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultReservationService is the service reservation recommendations cover unless ?service= is set.
+const defaultReservationService = "Amazon Elastic Compute Cloud - Compute"
+
+// GetCommitmentRecommendations returns a handler function that lists Savings Plans and Reserved
+// Instance purchase recommendations from Cost Explorer.
+func GetCommitmentRecommendations(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		commitmentType := c.DefaultQuery("type", "all")
+		planType := types.SupportedSavingsPlansType(c.DefaultQuery("savings_plans_type", string(types.SupportedSavingsPlansTypeComputeSp)))
+		term := types.TermInYears(c.DefaultQuery("term", string(types.TermInYearsOneYear)))
+		payment := types.PaymentOption(c.DefaultQuery("payment_option", string(types.PaymentOptionNoUpfront)))
+		lookback := types.LookbackPeriodInDays(c.DefaultQuery("lookback", string(types.LookbackPeriodInDaysThirtyDays)))
+		service := c.DefaultQuery("service", defaultReservationService)
+
+		// Validate parameters against the Cost Explorer enums
+		if commitmentType != "all" && commitmentType != "savings_plans" && commitmentType != "reservations" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type, use all, savings_plans or reservations"})
+			return
+		}
+		if !validEnum(planType, planType.Values()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid savings_plans_type, use COMPUTE_SP, EC2_INSTANCE_SP or SAGEMAKER_SP"})
+			return
+		}
+		if !validEnum(term, term.Values()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term, use ONE_YEAR or THREE_YEARS"})
+			return
+		}
+		if payment != types.PaymentOptionNoUpfront && payment != types.PaymentOptionPartialUpfront && payment != types.PaymentOptionAllUpfront {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment_option, use NO_UPFRONT, PARTIAL_UPFRONT or ALL_UPFRONT"})
+			return
+		}
+		if !validEnum(lookback, lookback.Values()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lookback, use SEVEN_DAYS, THIRTY_DAYS or SIXTY_DAYS"})
+			return
+		}
+
+		recommendations := []models.CommitmentRecommendation{}
+		if commitmentType != "reservations" {
+			savingsPlans, err := aws.GetSavingsPlansPurchaseRecommendations(c.Request.Context(), cfg, planType, term, payment, lookback)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			recommendations = append(recommendations, savingsPlans...)
+		}
+		if commitmentType != "savings_plans" {
+			reservations, err := aws.GetReservationPurchaseRecommendations(c.Request.Context(), cfg, service, term, payment, lookback)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			recommendations = append(recommendations, reservations...)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"recommendations": recommendations,
+		})
+	}
+}
+
+// SimulateCommitment returns a handler function that models the savings of a hypothetical hourly
+// commitment against on-demand usage stored from the CUR, to sanity-check recommendations.
+func SimulateCommitment(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hourlyCommitment, err := strconv.ParseFloat(c.Query("hourly_commitment"), 64)
+		if err != nil || hourlyCommitment <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hourly_commitment must be a positive number"})
+			return
+		}
+		term := strings.ToUpper(c.DefaultQuery("term", string(types.TermInYearsOneYear)))
+		discountRate, ok := commitment.DefaultDiscountRates[term]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term, use ONE_YEAR or THREE_YEARS"})
+			return
+		}
+		if rateStr := c.Query("discount_rate"); rateStr != "" {
+			discountRate, err = strconv.ParseFloat(rateStr, 64)
+			if err != nil || discountRate <= 0 || discountRate >= 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "discount_rate must be between 0 and 1"})
+				return
+			}
+		}
+		start, end, ok := parseDateRange(c)
+		if !ok {
+			return
+		}
+		simulation, err := aws.SimulateCommitment(cfg, start, end, c.Query("service"), hourlyCommitment, term, discountRate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"simulation": simulation,
+		})
+	}
+}
+
+// defaultRightsizingDays is the lookback for rightsizing recommendations unless start/end or days is set.
+const defaultRightsizingDays = 14
+
+// GetRightsizingRecommendations returns a handler function that recommends smaller EC2 and RDS
+// instance types from CloudWatch utilization percentiles, optionally cross-checked against
+// Compute Optimizer (?compute_optimizer=true).
+func GetRightsizingRecommendations(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resourceType := c.DefaultQuery("resource_type", "all")
+		if resourceType != "all" && resourceType != "ec2" && resourceType != "rds" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource_type, use all, ec2 or rds"})
+			return
+		}
+		useComputeOptimizer := c.Query("compute_optimizer") == "true"
+
+		var start, end time.Time
+		if c.Query("start") != "" || c.Query("end") != "" {
+			var ok bool
+			start, end, ok = parseDateRange(c)
+			if !ok {
+				return
+			}
+		} else {
+			days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultRightsizingDays)))
+			if err != nil || days < 3 || days > 90 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 3 and 90"})
+				return
+			}
+			end = time.Now()
+			start = end.AddDate(0, 0, -days)
+		}
+
+		recommendations, err := aws.ListRightsizingRecommendations(c.Request.Context(), cfg, start, end, resourceType, useComputeOptimizer)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		totalSavings := 0.0
+		for _, recommendation := range recommendations {
+			totalSavings += recommendation.EstimatedMonthlySavings
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"recommendations":                 recommendations,
+			"total_estimated_monthly_savings": totalSavings,
+			"start":                           start.Format("2006-01-02"),
+			"end":                             end.Format("2006-01-02"),
+		})
+	}
+}
+
+// validEnum reports whether value is one of the allowed enum values.
+func validEnum[T comparable](value T, allowed []T) bool {
+	for _, v := range allowed {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
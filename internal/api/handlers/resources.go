@@ -4,10 +4,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/deepanshumishra/devcost-api/internal/aws"
 	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/currency"
 	"github.com/deepanshumishra/devcost-api/internal/models"
 	"github.com/gin-gonic/gin"
 )
@@ -60,11 +61,8 @@ func GetResourcesByTags(cfg *config.Config) gin.HandlerFunc {
 // GetUnusedResources returns a handler function that lists unused AWS resources.
 func GetUnusedResources(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get query parameters for time range
-		startStr := c.Query("start") // e.g., "2025-05-01"
-		endStr := c.Query("end")     // e.g., "2025-05-07"
+		// Get query parameters
 		unusedForDaysStr := c.Query("unusedForDays")
-		var start, end time.Time
 		var err error
 
 		// Default unusedForDays for Secrets Manager
@@ -77,27 +75,8 @@ func GetUnusedResources(cfg *config.Config) gin.HandlerFunc {
 			}
 		}
 
-		if startStr != "" && endStr != "" {
-			start, err = time.Parse("2006-01-02", startStr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format, use YYYY-MM-DD"})
-				return
-			}
-			end, err = time.Parse("2006-01-02", endStr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format, use YYYY-MM-DD"})
-				return
-			}
-			if end.Before(start) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "End date must be after start date"})
-				return
-			}
-		} else if startStr == "" && endStr == "" {
-			// Default: last 7 days
-			end = time.Now()
-			start = end.AddDate(0, 0, -7)
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Both start and end dates must be provided together"})
+		start, end, ok := parseDateRange(c)
+		if !ok {
 			return
 		}
 
@@ -170,10 +149,8 @@ func GetTagCosts(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get query parameters
 		tagKey := c.Query("tag_key")
-		startStr := c.Query("start") // e.g., "2025-05-01"
-		endStr := c.Query("end")     // e.g., "2025-05-07"
-		var start, end time.Time
-		var err error
+		source := c.DefaultQuery("source", cfg.CostSource)
+		metric := cur.CostMetric(c.DefaultQuery("metric", string(cur.MetricUnblended)))
 
 		// Validate tag_key
 		if tagKey == "" {
@@ -181,33 +158,53 @@ func GetTagCosts(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Validate date range
-		if startStr != "" && endStr != "" {
-			start, err = time.Parse("2006-01-02", startStr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format, use YYYY-MM-DD"})
-				return
-			}
-			end, err = time.Parse("2006-01-02", endStr)
+		// Validate cost source and metric
+		if source != "cost_explorer" && source != "cur" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source, use cost_explorer or cur"})
+			return
+		}
+		if metric != cur.MetricUnblended && metric != cur.MetricAmortized && metric != cur.MetricNetAmortized {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric, use unblended, amortized or net_amortized"})
+			return
+		}
+		if metric == cur.MetricNetAmortized && source != "cost_explorer" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The net_amortized metric requires source=cost_explorer"})
+			return
+		}
+
+		// Validate date range and target currency
+		start, end, ok := parseDateRange(c)
+		if !ok {
+			return
+		}
+		targetCurrency, ok := parseCurrency(c, cfg)
+		if !ok {
+			return
+		}
+
+		// Answer from ingested CUR data without calling Cost Explorer
+		if source == "cur" {
+			costs, err := aws.GetTagCostsFromCUR(cfg, tagKey, start, end, metric)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format, use YYYY-MM-DD"})
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
-			if end.Before(start) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "End date must be after start date"})
-				return
+			if targetCurrency != "" {
+				if err := currency.ConvertTagCosts(c.Request.Context(), cfg.CurrencyRates, costs, targetCurrency, end); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
 			}
-		} else if startStr == "" && endStr == "" {
-			// Default: last 7 days
-			end = time.Now()
-			start = end.AddDate(0, 0, -7)
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Both start and end dates must be provided together"})
+			c.JSON(http.StatusOK, gin.H{
+				"tag_costs": costs,
+				"source":    source,
+				"metric":    metric,
+			})
 			return
 		}
 
 		// Fetch tag costs
-		costs, err := aws.GetTagCosts(cfg, tagKey, start, end)
+		costs, err := aws.GetTagCosts(cfg, tagKey, start, end, metric)
 		if err != nil {
 			if strings.Contains(err.Error(), "UnrecognizedClientException") {
 				mockCosts := []models.TagCost{
@@ -243,9 +240,19 @@ func GetTagCosts(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// Convert to the requested currency, recording the rate used per tag value
+		if targetCurrency != "" {
+			if err := currency.ConvertTagCosts(c.Request.Context(), cfg.CurrencyRates, costs, targetCurrency, end); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
 		// Return tag costs
 		c.JSON(http.StatusOK, gin.H{
 			"tag_costs": costs,
+			"source":    source,
+			"metric":    metric,
 		})
 	}
 }
\ No newline at end of file
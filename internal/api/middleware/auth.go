@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole returns a middleware that only allows requests whose X-API-Key maps to the given role.
+func RequireRole(cfg *config.Config, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		if apiKey == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "X-API-Key header is required"})
+			return
+		}
+
+		keyRole, ok := cfg.APIKeys[apiKey]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+		if keyRole != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint requires the " + role + " role"})
+			return
+		}
+
+		c.Set("role", keyRole)
+		c.Next()
+	}
+}
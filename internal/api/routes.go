@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/deepanshumishra/devcost-api/internal/api/handlers"
+	"github.com/deepanshumishra/devcost-api/internal/api/middleware"
 	"github.com/deepanshumishra/devcost-api/internal/config"
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +20,61 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 	// Get Unused Resources
 	r.GET("/resources/unused", handlers.GetUnusedResources(cfg))
 
+	// Get in-use resources with cheaper modern configurations
+	r.GET("/resources/optimizations", handlers.GetOptimizations(cfg))
+
 	// Get Cost by Tag
 	r.GET("/costs/tag", handlers.GetTagCosts(cfg))
-}
\ No newline at end of file
+
+	// Bedrock token usage and estimated spend per model
+	r.GET("/costs/bedrock", handlers.GetBedrockTokenUsage(cfg))
+
+	// Export costs in the FinOps FOCUS schema
+	r.GET("/costs/export", handlers.ExportCosts(cfg))
+
+	// Import FOCUS cost data from other clouds (admin only)
+	r.POST("/costs/import/focus", middleware.RequireRole(cfg, "admin"), handlers.ImportFOCUS(cfg))
+
+	// Cost allocation tag management (admin only)
+	tags := r.Group("/costs/allocation-tags", middleware.RequireRole(cfg, "admin"))
+	tags.GET("", handlers.GetCostAllocationTags(cfg))
+	tags.PUT("/status", handlers.UpdateCostAllocationTagsStatus(cfg))
+	tags.GET("/backfill", handlers.GetCostAllocationTagBackfills(cfg))
+	tags.POST("/backfill", handlers.StartCostAllocationTagBackfill(cfg))
+
+	// Cost and Usage Report ingestion (admin only)
+	curGroup := r.Group("/cur", middleware.RequireRole(cfg, "admin"))
+	curGroup.GET("/status", handlers.GetCURStatus(cfg))
+	curGroup.POST("/ingest", handlers.IngestCUR(cfg))
+
+	// Budgets per tag value, account or service; changes and manual evaluation are admin only
+	r.GET("/budgets", handlers.ListBudgets(cfg))
+	r.GET("/budgets/:id", handlers.GetBudget(cfg))
+	r.GET("/budgets/:id/status", handlers.GetBudgetStatus(cfg))
+	budgets := r.Group("/budgets", middleware.RequireRole(cfg, "admin"))
+	budgets.POST("", handlers.CreateBudget(cfg))
+	budgets.PUT("/:id", handlers.UpdateBudget(cfg))
+	budgets.DELETE("/:id", handlers.DeleteBudget(cfg))
+	budgets.POST("/evaluate", handlers.EvaluateBudgets(cfg))
+
+	// Chargeback statements per cost center; rules are admin only
+	r.GET("/chargeback/statements", handlers.GetChargebackStatements(cfg))
+	r.GET("/chargeback/statements/:cost_center", handlers.GetChargebackStatement(cfg))
+	chargebackGroup := r.Group("/chargeback/rules", middleware.RequireRole(cfg, "admin"))
+	chargebackGroup.GET("", handlers.GetChargebackRules(cfg))
+	chargebackGroup.PUT("", handlers.UpdateChargebackRules(cfg))
+
+	// Reserved Instance and Savings Plans utilization and coverage
+	commitments := r.Group("/commitments")
+	commitments.GET("/reservations/utilization", handlers.GetReservationUtilization(cfg))
+	commitments.GET("/reservations/coverage", handlers.GetReservationCoverage(cfg))
+	commitments.GET("/savings-plans/utilization", handlers.GetSavingsPlansUtilization(cfg))
+	commitments.GET("/savings-plans/coverage", handlers.GetSavingsPlansCoverage(cfg))
+
+	// Savings Plans and Reserved Instance purchase recommendations, and an offline simulator
+	r.GET("/recommendations/commitments", handlers.GetCommitmentRecommendations(cfg))
+	r.GET("/recommendations/commitments/simulate", handlers.SimulateCommitment(cfg))
+
+	// EC2 and RDS rightsizing recommendations
+	r.GET("/recommendations/rightsizing", handlers.GetRightsizingRecommendations(cfg))
+}
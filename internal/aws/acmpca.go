@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acmpca"
+	"github.com/aws/aws-sdk-go-v2/service/acmpca/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ListUnusedPrivateCAs identifies active AWS Private CA instances that issued no certificates in
+// the specified number of days (at most 90), per CloudTrail event history. Root CAs are skipped:
+// in a tiered PKI they only sign subordinate CAs, rarely and possibly for other accounts.
+func ListUnusedPrivateCAs(ctx context.Context, cfg *config.Config, unusedForDays int) ([]models.UnusedResource, error) {
+	client := acmpca.NewFromConfig(cfg.AWSConfig)
+	trailClient := cloudtrail.NewFromConfig(cfg.AWSConfig)
+	unusedCAs := []models.UnusedResource{}
+	since := time.Now().AddDate(0, 0, -unusedForDays)
+
+	paginator := acmpca.NewListCertificateAuthoritiesPaginator(client, &acmpca.ListCertificateAuthoritiesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list private certificate authorities: %v", err)
+			return nil, fmt.Errorf("failed to list private certificate authorities: %v", err)
+		}
+
+		for _, ca := range page.CertificateAuthorities {
+			caArn := aws.ToString(ca.Arn)
+			// Disabled CAs are still billed; pending or deleted ones are not
+			if ca.Status != types.CertificateAuthorityStatusActive && ca.Status != types.CertificateAuthorityStatusDisabled {
+				continue
+			}
+			if ca.Type == types.CertificateAuthorityTypeRoot {
+				continue
+			}
+			if ca.CreatedAt != nil && ca.CreatedAt.After(since) {
+				continue
+			}
+
+			used, err := hasCloudTrailEvent(ctx, trailClient, caArn, map[string]bool{"IssueCertificate": true}, since)
+			if err != nil {
+				log.Printf("Failed to look up CloudTrail events for private CA %s: %v", caArn, err)
+				continue
+			}
+			if used {
+				continue
+			}
+
+			monthlyCost := privateCAGeneralPurposeMonthlyPrice
+			if ca.UsageMode == types.CertificateAuthorityUsageModeShortLivedCertificate {
+				monthlyCost = privateCAShortLivedMonthlyPrice
+			}
+			unusedCAs = append(unusedCAs, models.UnusedResource{
+				ResourceType:         "acm-pca:certificate-authority",
+				ResourceID:           caArn,
+				Reason:               "No certificates issued in " + strconv.Itoa(min(unusedForDays, cloudTrailLookbackDays)) + " days (" + string(ca.Status) + ")",
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found unused private CA: %s", caArn)
+		}
+	}
+
+	return unusedCAs, nil
+}
@@ -0,0 +1,262 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// Aurora Serverless v2 clusters with a minimum above this many ACUs that never scale above it are
+// reported, as the minimum rather than load sets their cost.
+const auroraServerlessHighMinACU = 2.0
+
+// auroraServerlessLowestMinACU is the lowest minimum capacity Serverless v2 can keep while running.
+const auroraServerlessLowestMinACU = 0.5
+
+// ListUnusedAuroraClusters identifies Aurora clusters with no database connections between start
+// and end, and stopped clusters that AWS will start again after 7 days.
+func ListUnusedAuroraClusters(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := rds.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	unusedClusters := []models.UnusedResource{}
+	days := int(end.Sub(start).Hours() / 24)
+
+	stopTimes := getRDSStopTimes(ctx, client, types.SourceTypeDbCluster)
+
+	paginator := rds.NewDescribeDBClustersPaginator(client, &rds.DescribeDBClustersInput{
+		Filters: []types.Filter{{Name: aws.String("engine"), Values: []string{"aurora-mysql", "aurora-postgresql"}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Aurora clusters: %v", err)
+			return nil, fmt.Errorf("failed to describe Aurora clusters: %v", err)
+		}
+
+		for _, cluster := range page.DBClusters {
+			clusterID := aws.ToString(cluster.DBClusterIdentifier)
+			switch aws.ToString(cluster.Status) {
+			case "stopped":
+				unusedClusters = append(unusedClusters, models.UnusedResource{
+					ResourceType:         "rds:cluster",
+					ResourceID:           clusterID,
+					Reason:               stoppedRDSReason(stopTimes[clusterID]),
+					EstimatedMonthlyCost: auroraStorageMonthlyCost(ctx, cwClient, cluster),
+				})
+				log.Printf("Found unused Aurora cluster (stopped): %s", clusterID)
+				continue
+			case "available":
+			default:
+				continue
+			}
+
+			dimensions := []cwtypes.Dimension{{Name: aws.String("DBClusterIdentifier"), Value: aws.String(clusterID)}}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("connections", "AWS/RDS", "DatabaseConnections", "Maximum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Aurora cluster %s: %v", clusterID, err)
+				continue
+			}
+			if connections := values["connections"]; len(connections) == 0 || maxValue(connections) > 0 {
+				continue
+			}
+
+			unusedClusters = append(unusedClusters, models.UnusedResource{
+				ResourceType:         "rds:cluster",
+				ResourceID:           clusterID,
+				Reason:               fmt.Sprintf("No database connections for %d days on %d instances", days, len(cluster.DBClusterMembers)),
+				EstimatedMonthlyCost: auroraClusterMonthlyCost(ctx, cfg, client, region, cluster),
+			})
+			log.Printf("Found unused Aurora cluster (no connections): %s", clusterID)
+		}
+	}
+
+	return unusedClusters, nil
+}
+
+// ListAuroraOptimizations recommends the lowest minimum capacity for Aurora Serverless v2 clusters
+// whose minimum is above auroraServerlessHighMinACU and whose capacity never rose above it over
+// the last 14 days. Clusters with no connections are reported as unused instead.
+func ListAuroraOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	client := rds.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	end := time.Now()
+	start := end.AddDate(0, 0, -14)
+	optimizations := []models.Optimization{}
+
+	paginator := rds.NewDescribeDBClustersPaginator(client, &rds.DescribeDBClustersInput{
+		Filters: []types.Filter{{Name: aws.String("engine"), Values: []string{"aurora-mysql", "aurora-postgresql"}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Aurora clusters: %v", err)
+			return nil, fmt.Errorf("failed to describe Aurora clusters: %v", err)
+		}
+
+		for _, cluster := range page.DBClusters {
+			scaling := cluster.ServerlessV2ScalingConfiguration
+			if aws.ToString(cluster.Status) != "available" || scaling == nil || aws.ToFloat64(scaling.MinCapacity) <= auroraServerlessHighMinACU {
+				continue
+			}
+			clusterID := aws.ToString(cluster.DBClusterIdentifier)
+			minACU := aws.ToFloat64(scaling.MinCapacity)
+
+			dimensions := []cwtypes.Dimension{{Name: aws.String("DBClusterIdentifier"), Value: aws.String(clusterID)}}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("connections", "AWS/RDS", "DatabaseConnections", "Maximum", dimensions, 86400, true),
+				metricQuery("capacity", "AWS/RDS", "ServerlessDatabaseCapacity", "Maximum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Aurora cluster %s: %v", clusterID, err)
+				continue
+			}
+			if connections := values["connections"]; len(connections) == 0 || maxValue(connections) == 0 {
+				continue
+			}
+			if capacity := values["capacity"]; len(capacity) == 0 || maxValue(capacity) > minACU {
+				continue
+			}
+
+			serverlessInstances := countServerlessInstances(ctx, client, clusterID)
+			if serverlessInstances == 0 {
+				continue
+			}
+			optimizations = append(optimizations, models.Optimization{
+				Category:                 "capacity",
+				ResourceType:             "rds:cluster",
+				ResourceID:               clusterID,
+				CurrentConfiguration:     fmt.Sprintf("Serverless v2 minimum %.1f ACU on %d instances", minACU, serverlessInstances),
+				RecommendedConfiguration: fmt.Sprintf("Serverless v2 minimum %.1f ACU", auroraServerlessLowestMinACU),
+				Reason:                   "Capacity never rose above the minimum in 14 days; a lower minimum still scales up with load",
+				EstimatedMonthlySavings:  (minACU - auroraServerlessLowestMinACU) * auroraServerlessV2ACUHourlyPrice * float64(serverlessInstances) * hoursPerMonth,
+			})
+		}
+	}
+
+	log.Printf("Found %d Aurora Serverless v2 capacity optimizations", len(optimizations))
+	return optimizations, nil
+}
+
+// auroraStorageMonthlyCost estimates the monthly storage cost of a cluster from its peak
+// VolumeBytesUsed over the last 14 days, which covers the 7 days a cluster can stay stopped.
+// Aurora reports AllocatedStorage as 1 GB, so it is only a fallback when the metric is missing.
+func auroraStorageMonthlyCost(ctx context.Context, client *cloudwatch.Client, cluster types.DBCluster) float64 {
+	clusterID := aws.ToString(cluster.DBClusterIdentifier)
+	storageGB := float64(aws.ToInt32(cluster.AllocatedStorage))
+	end := time.Now()
+	dimensions := []cwtypes.Dimension{{Name: aws.String("DBClusterIdentifier"), Value: aws.String(clusterID)}}
+	values, err := getMetricData(ctx, client, []cwtypes.MetricDataQuery{
+		metricQuery("volume", "AWS/RDS", "VolumeBytesUsed", "Maximum", dimensions, 86400, true),
+	}, end.AddDate(0, 0, -14), end)
+	if err != nil {
+		log.Printf("Failed to get storage metrics for Aurora cluster %s: %v", clusterID, err)
+	} else if volume := values["volume"]; len(volume) > 0 {
+		storageGB = maxValue(volume) / bytesPerGB
+	}
+
+	price := auroraStoragePerGBMonthPrice
+	if aws.ToString(cluster.StorageType) == "aurora-iopt1" {
+		price = auroraIOOptimizedStoragePerGBMonthPrice
+	}
+	return storageGB * price
+}
+
+// auroraClusterMonthlyCost estimates the on-demand instance cost of a cluster's members, with
+// Serverless v2 instances at their minimum capacity.
+func auroraClusterMonthlyCost(ctx context.Context, cfg *config.Config, client *rds.Client, region string, cluster types.DBCluster) float64 {
+	result, err := client.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []types.Filter{{Name: aws.String("db-cluster-id"), Values: []string{aws.ToString(cluster.DBClusterIdentifier)}}},
+	})
+	if err != nil {
+		log.Printf("Failed to describe instances of Aurora cluster %s: %v", aws.ToString(cluster.DBClusterIdentifier), err)
+		return 0
+	}
+
+	hourly := 0.0
+	for _, instance := range result.DBInstances {
+		instanceClass := aws.ToString(instance.DBInstanceClass)
+		if instanceClass == "db.serverless" {
+			if cluster.ServerlessV2ScalingConfiguration != nil {
+				hourly += aws.ToFloat64(cluster.ServerlessV2ScalingConfiguration.MinCapacity) * auroraServerlessV2ACUHourlyPrice
+			}
+			continue
+		}
+		price, err := getRDSOnDemandPrice(ctx, cfg, region, instanceClass, aws.ToString(instance.Engine), aws.ToString(instance.LicenseModel), false)
+		if err != nil {
+			log.Printf("No on-demand price for %s: %v", instanceClass, err)
+			continue
+		}
+		hourly += price
+	}
+	return hourly * hoursPerMonth
+}
+
+// countServerlessInstances counts a cluster's Serverless v2 (db.serverless) instances.
+func countServerlessInstances(ctx context.Context, client *rds.Client, clusterID string) int {
+	result, err := client.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []types.Filter{{Name: aws.String("db-cluster-id"), Values: []string{clusterID}}},
+	})
+	if err != nil {
+		log.Printf("Failed to describe instances of Aurora cluster %s: %v", clusterID, err)
+		return 0
+	}
+	count := 0
+	for _, instance := range result.DBInstances {
+		if aws.ToString(instance.DBInstanceClass) == "db.serverless" {
+			count++
+		}
+	}
+	return count
+}
+
+// getRDSStopTimes returns when each DB instance or cluster of the source type was last stopped,
+// from the last 14 days of RDS events (the most RDS keeps).
+func getRDSStopTimes(ctx context.Context, client *rds.Client, sourceType types.SourceType) map[string]time.Time {
+	stopTimes := make(map[string]time.Time)
+	paginator := rds.NewDescribeEventsPaginator(client, &rds.DescribeEventsInput{
+		SourceType:      sourceType,
+		Duration:        aws.Int32(14 * 24 * 60),
+		EventCategories: []string{"notification"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS events: %v", err)
+			return stopTimes
+		}
+		for _, event := range page.Events {
+			message := strings.ToLower(aws.ToString(event.Message))
+			if !strings.Contains(message, "stopped") || strings.Contains(message, "start") {
+				continue
+			}
+			sourceID := aws.ToString(event.SourceIdentifier)
+			if date := aws.ToTime(event.Date); date.After(stopTimes[sourceID]) {
+				stopTimes[sourceID] = date
+			}
+		}
+	}
+	return stopTimes
+}
+
+// stoppedRDSReason explains that a stopped instance or cluster still bills storage and will be
+// started automatically 7 days after it was stopped.
+func stoppedRDSReason(stoppedAt time.Time) string {
+	if stoppedAt.IsZero() {
+		return "Stopped; storage is still billed and AWS starts it automatically after 7 days"
+	}
+	return fmt.Sprintf("Stopped since %s; storage is still billed and AWS starts it automatically on %s",
+		stoppedAt.Format("2006-01-02"), stoppedAt.AddDate(0, 0, 7).Format("2006-01-02"))
+}
@@ -0,0 +1,274 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/deepanshumishra/devcost-api/internal/rightsizing"
+)
+
+// Auto Scaling groups whose aggregate CPU never exceeds this percentage of their in-service
+// instances, and whose target groups received fewer than lbIdleRequests requests through their
+// load balancers, are idle.
+const asgIdleCPUPercent = 5.0
+
+// autoScalingGroupUsage is an Auto Scaling group's in-service capacity and hourly load.
+type autoScalingGroupUsage struct {
+	name               string
+	minSize            int32
+	instances          int32
+	instanceIDs        []string // in-service instances whose CPU is summed
+	hourlyPrice        float64 // on-demand price of all in-service instances
+	busyInstances      map[time.Time]float64
+	requests           float64
+	requestsUnknown    bool // request metrics for a target group could not be read
+	hasScalingPolicies bool
+}
+
+// idle reports whether the group's aggregate CPU and requests stayed below the idle thresholds.
+func (u autoScalingGroupUsage) idle() bool {
+	if len(u.busyInstances) == 0 || u.requestsUnknown {
+		return false
+	}
+	peakBusy := 0.0
+	for _, busy := range u.busyInstances {
+		peakBusy = max(peakBusy, busy)
+	}
+	return peakBusy*100/float64(u.instances) < asgIdleCPUPercent && u.requests < lbIdleRequests
+}
+
+// ListIdleAutoScalingGroups identifies Auto Scaling groups whose instances were idle together
+// between start and end.
+func ListIdleAutoScalingGroups(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	idleGroups, _, err := listIdleAutoScalingGroups(ctx, cfg, start, end)
+	return idleGroups, err
+}
+
+// listIdleAutoScalingGroups identifies idle Auto Scaling groups and also returns the instances of
+// every group it could judge. A group is idle as a whole at asgIdleCPUPercent of its capacity,
+// stricter than the per-instance EC2 check, since removing it removes the service; instances
+// of a group that is only partly idle are covered by ListAutoScalingOptimizations instead, as
+// terminating one only makes the group replace it.
+func listIdleAutoScalingGroups(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, map[string]bool, error) {
+	usages, err := getAutoScalingGroupUsage(ctx, cfg, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	idleGroups := []models.UnusedResource{}
+	judgedInstances := make(map[string]bool)
+
+	for _, usage := range usages {
+		if usage.requestsUnknown {
+			continue
+		}
+		for _, instanceID := range usage.instanceIDs {
+			judgedInstances[instanceID] = true
+		}
+		if !usage.idle() {
+			continue
+		}
+		idleGroups = append(idleGroups, models.UnusedResource{
+			ResourceType:         "autoscaling:group",
+			ResourceID:           usage.name,
+			Reason:               fmt.Sprintf("Aggregate CPU under %.0f%% of %d instances and %d requests for %d days (min size %d)", asgIdleCPUPercent, usage.instances, int(usage.requests), int(end.Sub(start).Hours()/24), usage.minSize),
+			EstimatedMonthlyCost: usage.hourlyPrice * hoursPerMonth,
+		})
+		log.Printf("Found idle Auto Scaling group: %s", usage.name)
+	}
+
+	return idleGroups, judgedInstances, nil
+}
+
+// ListAutoScalingOptimizations recommends a lower minimum size, or a scheduled off-hours minimum,
+// for Auto Scaling groups whose aggregate CPU over the last 14 days needs fewer instances.
+func ListAutoScalingOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	end := time.Now()
+	usages, err := getAutoScalingGroupUsage(ctx, cfg, end.AddDate(0, 0, -14), end)
+	if err != nil {
+		return nil, err
+	}
+	optimizations := []models.Optimization{}
+
+	for _, usage := range usages {
+		// Idle groups are reported as unused resources
+		if usage.idle() {
+			continue
+		}
+		recommendation, ok := rightsizing.RecommendCapacity(usage.busyInstances, usage.minSize, usage.hasScalingPolicies)
+		if !ok {
+			continue
+		}
+
+		// Only instances held by the minimum are saved; capacity above it is already scaling
+		atMinimum := min(usage.instances, usage.minSize)
+		alwaysSaved := max(0, atMinimum-recommendation.MinSize)
+		offHoursSaved := max(0, min(atMinimum, recommendation.MinSize)-recommendation.OffHoursMinSize)
+		instancePrice := usage.hourlyPrice / float64(usage.instances)
+		savings := instancePrice * hoursPerMonth * (float64(alwaysSaved) + float64(offHoursSaved)*rightsizing.OffHoursShare)
+		if savings <= 0 {
+			continue
+		}
+
+		recommended := fmt.Sprintf("min size %d", recommendation.MinSize)
+		if recommendation.OffHoursMinSize < recommendation.MinSize {
+			recommended += fmt.Sprintf(", scheduled to %d outside 08:00-20:00 UTC on weekdays", recommendation.OffHoursMinSize)
+		}
+		optimizations = append(optimizations, models.Optimization{
+			Category:                 "capacity",
+			ResourceType:             "autoscaling:group",
+			ResourceID:               usage.name,
+			CurrentConfiguration:     fmt.Sprintf("min size %d, %d in service", usage.minSize, usage.instances),
+			RecommendedConfiguration: recommended,
+			Reason:                   recommendation.Reason,
+			EstimatedMonthlySavings:  savings,
+		})
+	}
+
+	log.Printf("Found %d Auto Scaling capacity optimizations", len(optimizations))
+	return optimizations, nil
+}
+
+// getAutoScalingGroupUsage reads each group's in-service instances, their summed hourly CPU as
+// busy instances, requests to its target groups and whether it has scaling policies.
+func getAutoScalingGroupUsage(ctx context.Context, cfg *config.Config, start, end time.Time) ([]autoScalingGroupUsage, error) {
+	client := autoscaling.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	elbClient := elasticloadbalancingv2.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	prices := make(map[string]float64)
+	usages := []autoScalingGroupUsage{}
+
+	paginator := autoscaling.NewDescribeAutoScalingGroupsPaginator(client, &autoscaling.DescribeAutoScalingGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Auto Scaling groups: %v", err)
+			return nil, fmt.Errorf("failed to describe Auto Scaling groups: %v", err)
+		}
+
+		for _, group := range page.AutoScalingGroups {
+			groupName := aws.ToString(group.AutoScalingGroupName)
+			usage := autoScalingGroupUsage{name: groupName, minSize: aws.ToInt32(group.MinSize)}
+
+			// Sum the instances' CPU per hour; GetMetricData takes at most 500 queries
+			queries := []cwtypes.MetricDataQuery{}
+			for _, instance := range group.Instances {
+				if instance.LifecycleState != types.LifecycleStateInService || len(queries) >= 499 {
+					continue
+				}
+				instanceType := aws.ToString(instance.InstanceType)
+				if _, ok := prices[instanceType]; !ok {
+					price, err := getEC2OnDemandPrice(ctx, cfg, region, instanceType, "Linux/UNIX")
+					if err != nil {
+						log.Printf("No on-demand price for %s: %v", instanceType, err)
+					}
+					prices[instanceType] = price
+				}
+				usage.instances++
+				usage.instanceIDs = append(usage.instanceIDs, aws.ToString(instance.InstanceId))
+				usage.hourlyPrice += prices[instanceType]
+				dimensions := []cwtypes.Dimension{{Name: aws.String("InstanceId"), Value: instance.InstanceId}}
+				queries = append(queries, metricQuery(fmt.Sprintf("cpu%d", len(queries)), "AWS/EC2", "CPUUtilization", "Average", dimensions, 3600, false))
+			}
+			if usage.instances == 0 {
+				continue
+			}
+			queries = append(queries, cwtypes.MetricDataQuery{
+				Id:         aws.String("busy"),
+				Expression: aws.String(`SUM(METRICS("cpu"))/100`),
+			})
+			series, err := getMetricSeries(ctx, cwClient, queries, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Auto Scaling group %s: %v", groupName, err)
+				continue
+			}
+			usage.busyInstances = series["busy"]
+
+			if len(group.TargetGroupARNs) > 0 {
+				usage.requests, err = getTargetGroupRequests(ctx, elbClient, cwClient, group.TargetGroupARNs, start, end)
+				if err != nil {
+					log.Printf("Failed to get request metrics for Auto Scaling group %s: %v", groupName, err)
+					usage.requestsUnknown = true
+				}
+			}
+
+			// Without policies the minimum has to cover the peak, the cautious assumption
+			policies, err := client.DescribePolicies(ctx, &autoscaling.DescribePoliciesInput{AutoScalingGroupName: group.AutoScalingGroupName})
+			if err != nil {
+				log.Printf("Failed to describe scaling policies for Auto Scaling group %s, assuming none: %v", groupName, err)
+			} else {
+				usage.hasScalingPolicies = len(policies.ScalingPolicies) > 0
+			}
+
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, nil
+}
+
+// getTargetGroupRequests sums the requests each target group received through each of its
+// Application Load Balancers between start and end.
+func getTargetGroupRequests(ctx context.Context, elbClient *elasticloadbalancingv2.Client, cwClient *cloudwatch.Client, targetGroupArns []string, start, end time.Time) (float64, error) {
+	result, err := elbClient.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{TargetGroupArns: targetGroupArns})
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe target groups: %v", err)
+	}
+
+	queries := []cwtypes.MetricDataQuery{}
+	for _, targetGroup := range result.TargetGroups {
+		targetGroupDimension, err := elbMetricDimension(aws.ToString(targetGroup.TargetGroupArn), "targetgroup/", false)
+		if err != nil {
+			return 0, err
+		}
+		for _, lbArn := range targetGroup.LoadBalancerArns {
+			lbDimension, err := elbMetricDimension(lbArn, "loadbalancer/", true)
+			if err != nil {
+				return 0, err
+			}
+			dimensions := []cwtypes.Dimension{
+				{Name: aws.String("TargetGroup"), Value: aws.String(targetGroupDimension)},
+				{Name: aws.String("LoadBalancer"), Value: aws.String(lbDimension)},
+			}
+			queries = append(queries, metricQuery(fmt.Sprintf("requests%d", len(queries)), "AWS/ApplicationELB", "RequestCount", "Sum", dimensions, 86400, true))
+		}
+	}
+	if len(queries) == 0 {
+		return 0, nil
+	}
+
+	values, err := getMetricData(ctx, cwClient, queries, start, end)
+	if err != nil {
+		return 0, err
+	}
+	requests := 0.0
+	for _, series := range values {
+		requests += sumValues(series)
+	}
+	return requests, nil
+}
+
+// elbMetricDimension returns the CloudWatch dimension value for a load balancer or target group
+// ARN: the resource after the "loadbalancer/" prefix, or the whole "targetgroup/..." resource.
+func elbMetricDimension(resourceArn, prefix string, trimPrefix bool) (string, error) {
+	parsed, err := arn.Parse(resourceArn)
+	if err != nil || !strings.HasPrefix(parsed.Resource, prefix) {
+		return "", fmt.Errorf("unexpected load balancing ARN '%s'", resourceArn)
+	}
+	if trimPrefix {
+		return strings.TrimPrefix(parsed.Resource, prefix), nil
+	}
+	return parsed.Resource, nil
+}
@@ -2,12 +2,16 @@ package aws
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagent"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
@@ -15,9 +19,13 @@ import (
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
 
+// Provisioned throughput with invocations in fewer than this share of hours is reported as
+// underused.
+const bedrockProvisionedActiveHoursShare = 0.1
 
-// ListUnusedBedrockResources identifies unused Bedrock models and knowledge bases.
-func ListUnusedBedrockResources(cfg *config.Config, start, end time.Time, unusedForDays int) ([]models.UnusedResource, error) {
+// ListUnusedBedrockResources identifies unused Bedrock models, provisioned throughput, agents and
+// knowledge bases, and the OpenSearch Serverless collections behind unused knowledge bases.
+func ListUnusedBedrockResources(ctx context.Context, cfg *config.Config, start, end time.Time, unusedForDays int) ([]models.UnusedResource, error) {
 	// Initialize Bedrock client for custom models
 	bedrockClient := bedrock.NewFromConfig(cfg.AWSConfig)
 	// Initialize Bedrock Agent client for knowledge bases
@@ -26,12 +34,16 @@ func ListUnusedBedrockResources(cfg *config.Config, start, end time.Time, unused
 	// Initialize slice
 	unusedResources := []models.UnusedResource{}
 
+	// Each check is independent; a failed one is logged and the others still run
+	var errs []error
+
 	// List custom models (fine-tuned models, paid)
 	modelInput := &bedrock.ListCustomModelsInput{}
-	modelResult, err := bedrockClient.ListCustomModels(context.TODO(), modelInput)
+	modelResult, err := bedrockClient.ListCustomModels(ctx, modelInput)
 	if err != nil {
 		log.Printf("Failed to list Bedrock custom models: %v", err)
-		return unusedResources, err
+		errs = append(errs, fmt.Errorf("failed to list Bedrock custom models: %v", err))
+		modelResult = &bedrock.ListCustomModelsOutput{}
 	}
 
 	// Check for unused custom models
@@ -51,34 +63,341 @@ func ListUnusedBedrockResources(cfg *config.Config, start, end time.Time, unused
 		}
 	}
 
-	// List knowledge bases
-	kbInput := &bedrockagent.ListKnowledgeBasesInput{}
-	kbResult, err := agentClient.ListKnowledgeBases(context.TODO(), kbInput)
-	if err != nil {
-		log.Printf("Failed to list Bedrock knowledge bases: %v", err)
-		return unusedResources, err
+	// Provisioned throughput is billed per model unit hour whether or not it is invoked
+	if provisioned, err := listUnderusedProvisionedThroughput(ctx, cfg, bedrockClient, start, end); err != nil {
+		errs = append(errs, err)
+	} else {
+		unusedResources = append(unusedResources, provisioned...)
+	}
+
+	if agents, err := listUnusedBedrockAgents(ctx, cfg, agentClient, start, end); err != nil {
+		errs = append(errs, err)
+	} else {
+		unusedResources = append(unusedResources, agents...)
 	}
 
-	// Check for unused knowledge bases
-	for _, kb := range kbResult.KnowledgeBaseSummaries {
-		isUnused, err := isBedrockKBUnused(cfg, aws.ToString(kb.KnowledgeBaseId), start, end)
+	// Check for unused knowledge bases, tracking which vector store collections are still queried
+	usedCollections := make(map[string]bool)
+	unusedCollections := make(map[string][]string)
+	kbPaginator := bedrockagent.NewListKnowledgeBasesPaginator(agentClient, &bedrockagent.ListKnowledgeBasesInput{})
+	for kbPaginator.HasMorePages() {
+		kbResult, err := kbPaginator.NextPage(ctx)
 		if err != nil {
-			log.Printf("Failed to check usage for Bedrock KB %s: %v", aws.ToString(kb.KnowledgeBaseId), err)
-			continue
+			log.Printf("Failed to list Bedrock knowledge bases: %v", err)
+			errs = append(errs, fmt.Errorf("failed to list Bedrock knowledge bases: %v", err))
+			break
 		}
-		if isUnused {
+
+		for _, kb := range kbResult.KnowledgeBaseSummaries {
+			kbID := aws.ToString(kb.KnowledgeBaseId)
+			isUnused, err := isBedrockKBUnused(cfg, kbID, start, end)
+			if err != nil {
+				log.Printf("Failed to check usage for Bedrock KB %s: %v", kbID, err)
+				continue
+			}
+
+			collectionArn := ""
+			kbDetails, err := agentClient.GetKnowledgeBase(ctx, &bedrockagent.GetKnowledgeBaseInput{KnowledgeBaseId: kb.KnowledgeBaseId})
+			if err != nil {
+				log.Printf("Failed to get Bedrock KB %s: %v", kbID, err)
+			} else if storage := kbDetails.KnowledgeBase.StorageConfiguration; storage != nil && storage.OpensearchServerlessConfiguration != nil {
+				collectionArn = aws.ToString(storage.OpensearchServerlessConfiguration.CollectionArn)
+			}
+
+			if !isUnused {
+				if collectionArn != "" {
+					usedCollections[collectionArn] = true
+				}
+				continue
+			}
 			unusedResources = append(unusedResources, models.UnusedResource{
 				ResourceType: "bedrock:knowledge-base",
-				ResourceID:   aws.ToString(kb.KnowledgeBaseId),
+				ResourceID:   kbID,
 				Reason:       "No queries for " + strconv.Itoa(unusedForDays) + " days",
 			})
-			log.Printf("Found unused Bedrock knowledge base: %s", aws.ToString(kb.KnowledgeBaseId))
+			log.Printf("Found unused Bedrock knowledge base: %s", kbID)
+			if collectionArn != "" {
+				unusedCollections[collectionArn] = append(unusedCollections[collectionArn], kbID)
+			}
 		}
 	}
 
+	// A collection keeps billing its minimum OCUs until it is deleted, even with no queries
+	for collectionArn, kbIDs := range unusedCollections {
+		if usedCollections[collectionArn] {
+			continue
+		}
+		unusedResources = append(unusedResources, models.UnusedResource{
+			ResourceType:         "aoss:collection",
+			ResourceID:           collectionArn,
+			Reason:               "Vector store for unused knowledge bases " + strings.Join(kbIDs, ", "),
+			EstimatedMonthlyCost: openSearchServerlessMinOCUs * openSearchServerlessOCUHourlyPrice * hoursPerMonth,
+		})
+		log.Printf("Found OpenSearch Serverless collection behind unused Bedrock knowledge bases: %s", collectionArn)
+	}
+
+	if len(unusedResources) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to list unused Bedrock resources: %v", errs[0])
+	}
 	return unusedResources, nil
 }
 
+// listUnderusedProvisionedThroughput finds in-service Provisioned Throughput with invocations in
+// less than bedrockProvisionedActiveHoursShare of the hours between start and end.
+func listUnderusedProvisionedThroughput(ctx context.Context, cfg *config.Config, client *bedrock.Client, start, end time.Time) ([]models.UnusedResource, error) {
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	underused := []models.UnusedResource{}
+	totalHours := int(end.Sub(start).Hours())
+
+	paginator := bedrock.NewListProvisionedModelThroughputsPaginator(client, &bedrock.ListProvisionedModelThroughputsInput{
+		StatusEquals: bedrocktypes.ProvisionedModelStatusInService,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list Bedrock provisioned throughput: %v", err)
+			return nil, fmt.Errorf("failed to list Bedrock provisioned throughput: %v", err)
+		}
+
+		for _, throughput := range page.ProvisionedModelSummaries {
+			provisionedArn := aws.ToString(throughput.ProvisionedModelArn)
+			dimensions := []types.Dimension{{Name: aws.String("ModelId"), Value: aws.String(provisionedArn)}}
+			values, err := getMetricData(ctx, cwClient, []types.MetricDataQuery{
+				metricQuery("invocations", "AWS/Bedrock", "Invocations", "Sum", dimensions, 3600, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Bedrock provisioned throughput %s: %v", provisionedArn, err)
+				continue
+			}
+
+			activeHours := 0
+			for _, value := range values["invocations"] {
+				if value > 0 {
+					activeHours++
+				}
+			}
+			if totalHours == 0 || float64(activeHours)/float64(totalHours) >= bedrockProvisionedActiveHoursShare {
+				continue
+			}
+
+			reason := fmt.Sprintf("Invocations in %d of %d hours on %d model units", activeHours, totalHours, aws.ToInt32(throughput.ModelUnits))
+			if throughput.CommitmentDuration != "" {
+				reason += fmt.Sprintf(", %s commitment", throughput.CommitmentDuration)
+			} else {
+				reason += ", no commitment"
+			}
+			// Committed throughput is billed until the term ends, but the cost is still what it wastes
+			monthlyCost := 0.0
+			if price, ok := bedrockModelUnitPrice(aws.ToString(throughput.FoundationModelArn), throughput.CommitmentDuration); ok {
+				monthlyCost = price * float64(aws.ToInt32(throughput.ModelUnits)) * hoursPerMonth
+			} else {
+				log.Printf("No model unit price for Bedrock provisioned throughput %s on %s", provisionedArn, aws.ToString(throughput.FoundationModelArn))
+			}
+			underused = append(underused, models.UnusedResource{
+				ResourceType:         "bedrock:provisioned-model",
+				ResourceID:           provisionedArn,
+				Reason:               reason,
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found underused Bedrock provisioned throughput: %s", provisionedArn)
+		}
+	}
+
+	return underused, nil
+}
+
+// listUnusedBedrockAgents finds agents with no invocations through any alias between start and
+// end. Agents have no standing charge, but idle ones usually point at other billed resources.
+func listUnusedBedrockAgents(ctx context.Context, cfg *config.Config, client *bedrockagent.Client, start, end time.Time) ([]models.UnusedResource, error) {
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	unusedAgents := []models.UnusedResource{}
+
+	// Agent metrics are keyed by alias ARN, ".../agent-alias/<agent ID>/<alias ID>"
+	metrics, err := listBedrockMetrics(ctx, cwClient, "AWS/Bedrock/Agents", "InvocationCount", "AgentAliasArn")
+	if err != nil {
+		log.Printf("Failed to list Bedrock agent metrics: %v", err)
+		return nil, fmt.Errorf("failed to list Bedrock agent metrics: %v", err)
+	}
+	invocations, err := sumMetricsByDimension(ctx, cwClient, metrics, "AgentAliasArn", start, end)
+	if err != nil {
+		log.Printf("Failed to get Bedrock agent metrics: %v", err)
+		return nil, fmt.Errorf("failed to get Bedrock agent metrics: %v", err)
+	}
+	invokedAgents := make(map[string]bool)
+	for aliasArn, count := range invocations {
+		parts := strings.Split(aliasArn, "/")
+		if len(parts) == 3 && count > 0 {
+			invokedAgents[parts[1]] = true
+		}
+	}
+
+	paginator := bedrockagent.NewListAgentsPaginator(client, &bedrockagent.ListAgentsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list Bedrock agents: %v", err)
+			return nil, fmt.Errorf("failed to list Bedrock agents: %v", err)
+		}
+
+		for _, agent := range page.AgentSummaries {
+			agentID := aws.ToString(agent.AgentId)
+			if invokedAgents[agentID] {
+				continue
+			}
+			unusedAgents = append(unusedAgents, models.UnusedResource{
+				ResourceType: "bedrock:agent",
+				ResourceID:   agentID,
+				Reason:       fmt.Sprintf("No invocations for %d days", int(end.Sub(start).Hours()/24)),
+			})
+			log.Printf("Found unused Bedrock agent: %s", agentID)
+		}
+	}
+
+	return unusedAgents, nil
+}
+
+// ListBedrockTokenUsage reports input and output tokens and invocations per model between start
+// and end, with an on-demand cost estimate for models with a known token price. Models are found
+// through ListMetrics, which only returns metrics with data in the last two weeks, so models last
+// invoked before then are missing from older ranges.
+func ListBedrockTokenUsage(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.BedrockModelUsage, error) {
+	client := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	usage := make(map[string]*models.BedrockModelUsage)
+
+	for _, metricName := range []string{"InputTokenCount", "OutputTokenCount", "Invocations"} {
+		metrics, err := listBedrockMetrics(ctx, client, "AWS/Bedrock", metricName, "ModelId")
+		if err != nil {
+			log.Printf("Failed to list Bedrock %s metrics: %v", metricName, err)
+			return nil, fmt.Errorf("failed to list Bedrock %s metrics: %v", metricName, err)
+		}
+		totals, err := sumMetricsByDimension(ctx, client, metrics, "ModelId", start, end)
+		if err != nil {
+			log.Printf("Failed to get Bedrock %s metrics: %v", metricName, err)
+			return nil, fmt.Errorf("failed to get Bedrock %s metrics: %v", metricName, err)
+		}
+
+		for modelID, total := range totals {
+			if usage[modelID] == nil {
+				usage[modelID] = &models.BedrockModelUsage{ModelID: modelID}
+			}
+			switch metricName {
+			case "InputTokenCount":
+				usage[modelID].InputTokens = int64(total)
+			case "OutputTokenCount":
+				usage[modelID].OutputTokens = int64(total)
+			case "Invocations":
+				usage[modelID].Invocations = int64(total)
+			}
+		}
+	}
+
+	usageList := []models.BedrockModelUsage{}
+	for _, modelUsage := range usage {
+		if price, ok := bedrockTokenPrice(modelUsage.ModelID); ok {
+			modelUsage.EstimatedCost = float64(modelUsage.InputTokens)/1000*price.input + float64(modelUsage.OutputTokens)/1000*price.output
+		}
+		usageList = append(usageList, *modelUsage)
+	}
+	sort.Slice(usageList, func(i, j int) bool {
+		if usageList[i].EstimatedCost != usageList[j].EstimatedCost {
+			return usageList[i].EstimatedCost > usageList[j].EstimatedCost
+		}
+		return usageList[i].InputTokens+usageList[i].OutputTokens > usageList[j].InputTokens+usageList[j].OutputTokens
+	})
+
+	return usageList, nil
+}
+
+// bedrockModelUnitPrice looks up the hourly price of one Provisioned Throughput model unit for a
+// foundation model ARN and commitment term, matching the longest known model ID prefix.
+func bedrockModelUnitPrice(foundationModelArn string, commitment bedrocktypes.CommitmentDuration) (float64, bool) {
+	modelID := foundationModelArn
+	if i := strings.LastIndex(modelID, "/"); i >= 0 {
+		modelID = modelID[i+1:]
+	}
+
+	match := ""
+	for prefix := range bedrockModelUnitPrices {
+		if strings.HasPrefix(modelID, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return 0, false
+	}
+	price, ok := bedrockModelUnitPrices[match][commitment]
+	return price, ok
+}
+
+// bedrockTokenPrice looks up the on-demand token price for a model ID, ignoring cross-region
+// inference profile prefixes such as "us." and matching the longest known model ID prefix.
+func bedrockTokenPrice(modelID string) (tokenPrice, bool) {
+	if i := strings.LastIndex(modelID, "/"); i >= 0 {
+		modelID = modelID[i+1:]
+	}
+	for _, prefix := range []string{"us.", "eu.", "apac.", "global."} {
+		modelID = strings.TrimPrefix(modelID, prefix)
+	}
+
+	match := ""
+	for prefix := range bedrockTokenPrices {
+		if strings.HasPrefix(modelID, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return tokenPrice{}, false
+	}
+	return bedrockTokenPrices[match], true
+}
+
+// listBedrockMetrics lists the metrics of a namespace and name that have the given dimension.
+func listBedrockMetrics(ctx context.Context, client *cloudwatch.Client, namespace, metricName, dimension string) ([]types.Metric, error) {
+	metrics := []types.Metric{}
+	paginator := cloudwatch.NewListMetricsPaginator(client, &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metricName),
+		Dimensions: []types.DimensionFilter{{Name: aws.String(dimension)}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, page.Metrics...)
+	}
+	return metrics, nil
+}
+
+// sumMetricsByDimension sums each metric between start and end and totals the sums by the value
+// of the given dimension. GetMetricData accepts at most 500 queries per request.
+func sumMetricsByDimension(ctx context.Context, client *cloudwatch.Client, metrics []types.Metric, dimension string, start, end time.Time) (map[string]float64, error) {
+	totals := make(map[string]float64)
+	for batchStart := 0; batchStart < len(metrics); batchStart += 500 {
+		batch := metrics[batchStart:min(batchStart+500, len(metrics))]
+		queries := make([]types.MetricDataQuery, 0, len(batch))
+		keys := make(map[string]string)
+		for i, metric := range batch {
+			id := fmt.Sprintf("m%d", i)
+			queries = append(queries, metricQuery(id, aws.ToString(metric.Namespace), aws.ToString(metric.MetricName), "Sum", metric.Dimensions, 86400, true))
+			for _, d := range metric.Dimensions {
+				if aws.ToString(d.Name) == dimension {
+					keys[id] = aws.ToString(d.Value)
+				}
+			}
+		}
+
+		values, err := getMetricData(ctx, client, queries, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for id, series := range values {
+			totals[keys[id]] += sumValues(series)
+		}
+	}
+	return totals, nil
+}
+
 // isBedrockModelUnused checks if a Bedrock model has no inference calls.
 func isBedrockModelUnused(cfg *config.Config, modelArn string, start, end time.Time) (bool, error) {
 	client := cloudwatch.NewFromConfig(cfg.AWSConfig)
@@ -0,0 +1,251 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/budget"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// GetBudgetStatus computes month-to-date and forecasted spend for a budget without firing alerts.
+func GetBudgetStatus(ctx context.Context, cfg *config.Config, b models.Budget, now time.Time) (models.BudgetStatus, error) {
+	monthStart, monthEnd := budget.MonthBounds(now)
+	actual, forecast, sourceCurrency, err := getBudgetSpend(ctx, cfg, b, monthStart, monthEnd, now)
+	if err != nil {
+		return models.BudgetStatus{}, err
+	}
+
+	// Budgets without a currency track the billing currency; others are converted to theirs
+	var exchangeRate *models.ExchangeRate
+	if b.Currency == "" {
+		b.Currency = sourceCurrency
+	} else if !strings.EqualFold(b.Currency, sourceCurrency) {
+		if cfg.CurrencyRates == nil {
+			return models.BudgetStatus{}, fmt.Errorf("budget currency %s differs from billing currency %s and no exchange rates are configured", b.Currency, sourceCurrency)
+		}
+		rate, err := cfg.CurrencyRates.Rate(ctx, sourceCurrency, b.Currency, now)
+		if err != nil {
+			return models.BudgetStatus{}, fmt.Errorf("failed to convert spend for budget '%s': %v", b.Name, err)
+		}
+		actual *= rate.Rate
+		forecast *= rate.Rate
+		exchangeRate = &rate
+	}
+
+	status := models.BudgetStatus{
+		Budget:          b,
+		Month:           monthStart.Format("2006-01"),
+		ActualSpend:     actual,
+		ForecastedSpend: forecast,
+		ActualPercent:   actual / b.MonthlyLimit * 100,
+		ForecastPercent: forecast / b.MonthlyLimit * 100,
+		Alerts:          []models.BudgetAlert{},
+		ExchangeRate:    exchangeRate,
+	}
+	if exchangeRate != nil {
+		status.SourceCurrency = sourceCurrency
+	}
+	return status, nil
+}
+
+// budgetEvaluationMu serializes budget evaluations, so the hourly run and POST /budgets/evaluate
+// never record alerts for the same budget at the same time.
+var budgetEvaluationMu sync.Mutex
+
+// EvaluateBudgets checks every budget against its thresholds, sends alerts for newly crossed
+// thresholds and records them so each threshold fires at most once per month. A budget that
+// fails to evaluate is logged and skipped; the error names the first one.
+func EvaluateBudgets(ctx context.Context, cfg *config.Config) ([]models.BudgetStatus, error) {
+	budgetEvaluationMu.Lock()
+	defer budgetEvaluationMu.Unlock()
+
+	notifiers := []budget.Notifier{budget.LogNotifier{}}
+	if cfg.BudgetWebhookURL != "" {
+		notifiers = append(notifiers, budget.WebhookNotifier{URL: cfg.BudgetWebhookURL})
+	}
+
+	now := time.Now().UTC()
+	statuses := []models.BudgetStatus{}
+	var evaluateErr error
+	for _, b := range cfg.BudgetStore.List() {
+		status, err := GetBudgetStatus(ctx, cfg, b, now)
+		if err != nil {
+			log.Printf("Failed to evaluate budget %s: %v", b.Name, err)
+			if evaluateErr == nil {
+				evaluateErr = fmt.Errorf("failed to evaluate budget '%s': %v", b.Name, err)
+			}
+			continue
+		}
+
+		// List returns copies, so alerts are only marked as fired in the store once recorded
+		evaluated := status.Budget
+		status.Alerts = budget.Evaluate(&evaluated, status.Month, status.ActualSpend, status.ForecastedSpend, now)
+		for _, alert := range status.Alerts {
+			for _, notifier := range notifiers {
+				if err := notifier.Notify(ctx, alert); err != nil {
+					log.Printf("Failed to send alert for budget %s: %v", b.Name, err)
+				}
+			}
+		}
+		if len(status.Alerts) > 0 {
+			if err := cfg.BudgetStore.RecordAlerts(b.ID, evaluated.FiredAlerts); err != nil {
+				log.Printf("Failed to record alerts for budget %s: %v", b.Name, err)
+				evaluated.FiredAlerts = b.FiredAlerts
+			}
+		}
+
+		status.Budget = evaluated
+		statuses = append(statuses, status)
+	}
+
+	return statuses, evaluateErr
+}
+
+// getBudgetSpend returns month-to-date spend, the forecast for the whole month and the billing
+// currency, from ingested CUR data when it is the configured cost source and from Cost Explorer otherwise.
+func getBudgetSpend(ctx context.Context, cfg *config.Config, b models.Budget, monthStart, monthEnd, now time.Time) (float64, float64, string, error) {
+	if cfg.CostSource == "cur" {
+		if cfg.CURStore == nil || cfg.CURStore.Len() == 0 {
+			return 0, 0, "", fmt.Errorf("no CUR data has been ingested")
+		}
+		actual := 0.0
+		currency := ""
+		var mixed string
+		cfg.CURStore.Scan(monthStart, monthEnd, func(item cur.LineItem) {
+			if !budgetScopeMatches(b.Scope, item) {
+				return
+			}
+			itemCurrency := item.Currency
+			if itemCurrency == "" {
+				itemCurrency = "USD"
+			}
+			if currency == "" {
+				currency = itemCurrency
+			} else if itemCurrency != currency {
+				mixed = itemCurrency
+				return
+			}
+			actual += item.UnblendedCost
+		})
+		if mixed != "" {
+			return 0, 0, "", fmt.Errorf("CUR spend for budget %s is billed in both %s and %s", b.Name, currency, mixed)
+		}
+		if currency == "" {
+			currency = "USD"
+		}
+		return actual, budget.ProjectLinear(actual, monthStart, monthEnd, now), currency, nil
+	}
+
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	filter := budgetScopeFilter(b.Scope)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	result, err := client.GetCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &types.DateInterval{
+			Start: aws.String(monthStart.Format("2006-01-02")),
+			End:   aws.String(tomorrow.Format("2006-01-02")),
+		},
+		Granularity: types.GranularityMonthly,
+		Metrics:     []string{"UnblendedCost"},
+		Filter:      filter,
+	})
+	if err != nil {
+		log.Printf("Failed to get spend for budget %s: %v", b.Name, err)
+		return 0, 0, "", fmt.Errorf("failed to fetch spend: %v", err)
+	}
+
+	actual := 0.0
+	currency := "USD"
+	for _, period := range result.ResultsByTime {
+		total := period.Total["UnblendedCost"]
+		amount, err := strconv.ParseFloat(aws.ToString(total.Amount), 64)
+		if err != nil {
+			log.Printf("Failed to parse spend for budget %s: %v", b.Name, err)
+			continue
+		}
+		actual += amount
+		if unit := aws.ToString(total.Unit); unit != "" {
+			currency = unit
+		}
+	}
+
+	if !tomorrow.Before(monthEnd) {
+		return actual, actual, currency, nil
+	}
+
+	forecast, err := client.GetCostForecast(ctx, &costexplorer.GetCostForecastInput{
+		TimePeriod: &types.DateInterval{
+			Start: aws.String(tomorrow.Format("2006-01-02")),
+			End:   aws.String(monthEnd.Format("2006-01-02")),
+		},
+		Granularity: types.GranularityMonthly,
+		Metric:      types.MetricUnblendedCost,
+		Filter:      filter,
+	})
+	if err != nil {
+		// Cost Explorer needs some history to forecast; fall back to the month's run rate
+		log.Printf("Failed to get forecast for budget %s, using linear projection: %v", b.Name, err)
+		return actual, budget.ProjectLinear(actual, monthStart, monthEnd, now), currency, nil
+	}
+	remaining, err := strconv.ParseFloat(aws.ToString(forecast.Total.Amount), 64)
+	if err != nil {
+		return actual, budget.ProjectLinear(actual, monthStart, monthEnd, now), currency, nil
+	}
+
+	return actual, actual + remaining, currency, nil
+}
+
+// budgetScopeFilter converts a budget scope to a Cost Explorer filter expression.
+func budgetScopeFilter(scope models.BudgetScope) *types.Expression {
+	switch scope.Type {
+	case "tag":
+		return &types.Expression{
+			Tags: &types.TagValues{
+				Key:    aws.String(scope.Key),
+				Values: []string{scope.Value},
+			},
+		}
+	case "account":
+		return &types.Expression{
+			Dimensions: &types.DimensionValues{
+				Key:    types.DimensionLinkedAccount,
+				Values: []string{scope.Value},
+			},
+		}
+	default:
+		return &types.Expression{
+			Dimensions: &types.DimensionValues{
+				Key:    types.DimensionService,
+				Values: []string{scope.Value},
+			},
+		}
+	}
+}
+
+// budgetScopeMatches reports whether a CUR line item falls within a budget scope. Budgets
+// track AWS spend, so line items imported from other clouds never match.
+func budgetScopeMatches(scope models.BudgetScope, item cur.LineItem) bool {
+	if item.Provider != "AWS" {
+		return false
+	}
+	switch scope.Type {
+	case "tag":
+		value, ok := item.Tag(scope.Key)
+		return ok && value == scope.Value
+	case "account":
+		return item.AccountID == scope.Value
+	default:
+		return item.ServiceName == scope.Value || item.ProductCode == scope.Value
+	}
+}
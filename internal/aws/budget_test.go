@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+func TestBudgetSpendFromCUR(t *testing.T) {
+	monthStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd, now := monthStart.AddDate(0, 1, 0), monthStart.AddDate(0, 0, 10)
+	tags := map[string]string{"project": "dev-cluster"}
+	b := models.Budget{Name: "dev", Scope: models.BudgetScope{Type: "tag", Key: "project", Value: "dev-cluster"}}
+
+	tests := []struct {
+		name     string
+		items    map[string][]cur.LineItem
+		want     float64
+		currency string
+		wantErr  bool
+	}{
+		{
+			name: "other clouds are ignored",
+			items: map[string][]cur.LineItem{
+				"cur":             {{Provider: "AWS", UsageStart: monthStart, UnblendedCost: 2, Tags: tags}},
+				"focus/azure.csv": {{Provider: "Microsoft", UsageStart: monthStart, UnblendedCost: 3, Currency: "EUR", Tags: tags}},
+			},
+			want:     2,
+			currency: "USD",
+		},
+		{
+			name: "billing currency is kept",
+			items: map[string][]cur.LineItem{
+				"cur": {{Provider: "AWS", UsageStart: monthStart, UnblendedCost: 2, Currency: "EUR", Tags: tags}},
+			},
+			want:     2,
+			currency: "EUR",
+		},
+		{
+			name: "mixed currencies are rejected",
+			items: map[string][]cur.LineItem{
+				"cur":       {{Provider: "AWS", UsageStart: monthStart, UnblendedCost: 2, Tags: tags}},
+				"cur-payer": {{Provider: "AWS", UsageStart: monthStart, UnblendedCost: 3, Currency: "EUR", Tags: tags}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, _ := cur.NewStore("")
+			for source, items := range tt.items {
+				store.Replace(source, "1", items)
+			}
+			cfg := &config.Config{CostSource: "cur", CURStore: store}
+
+			actual, _, currency, err := getBudgetSpend(context.Background(), cfg, b, monthStart, monthEnd, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %.2f %s", actual, currency)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tt.want || currency != tt.currency {
+				t.Errorf("expected %.2f %s, got %.2f %s", tt.want, tt.currency, actual, currency)
+			}
+		})
+	}
+}
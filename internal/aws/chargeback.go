@@ -0,0 +1,188 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/chargeback"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/focus"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// creditRecordTypes are the Cost Explorer record types charged back as credits rather than cost.
+var creditRecordTypes = []string{"Credit", "Refund", "Enterprise Discount Program Discount", "Bundled Discount", "Private Rate Discount"}
+
+// GetChargebackStatements builds the month's chargeback statements for every cost center,
+// from ingested CUR data when it is the configured cost source and from Cost Explorer otherwise.
+func GetChargebackStatements(ctx context.Context, cfg *config.Config, rules models.ChargebackRules, month time.Time) ([]models.ChargebackStatement, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	var rows []chargeback.CostRow
+	var currency string
+	var err error
+	if cfg.CostSource == "cur" {
+		rows, currency, err = getChargebackRowsFromCUR(ctx, cfg, rules.TagKey, start, end)
+	} else {
+		rows, currency, err = getChargebackRowsFromCostExplorer(ctx, cfg, rules.TagKey, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+
+	log.Printf("Building chargeback statements for %s from %d cost rows", start.Format("2006-01"), len(rows))
+	return chargeback.Build(rules, start.Format("2006-01"), currency, rows), nil
+}
+
+// getChargebackRowsFromCostExplorer fetches monthly cost by tag value and service, querying
+// credits separately so they can be attributed on their own.
+func getChargebackRowsFromCostExplorer(ctx context.Context, cfg *config.Config, tagKey string, start, end time.Time) ([]chargeback.CostRow, string, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	creditFilter := &types.Expression{
+		Dimensions: &types.DimensionValues{
+			Key:    types.DimensionRecordType,
+			Values: creditRecordTypes,
+		},
+	}
+
+	rows := []chargeback.CostRow{}
+	currency := ""
+	for _, credit := range []bool{false, true} {
+		filter := creditFilter
+		if !credit {
+			filter = &types.Expression{Not: creditFilter}
+		}
+		input := &costexplorer.GetCostAndUsageInput{
+			TimePeriod: &types.DateInterval{
+				Start: aws.String(start.Format("2006-01-02")),
+				End:   aws.String(end.Format("2006-01-02")),
+			},
+			Granularity: types.GranularityMonthly,
+			Metrics:     []string{"UnblendedCost"},
+			Filter:      filter,
+			GroupBy: []types.GroupDefinition{
+				{
+					Type: types.GroupDefinitionTypeTag,
+					Key:  aws.String(tagKey),
+				},
+				{
+					Type: types.GroupDefinitionTypeDimension,
+					Key:  aws.String("SERVICE"),
+				},
+			},
+		}
+
+		for {
+			result, err := client.GetCostAndUsage(ctx, input)
+			if err != nil {
+				log.Printf("Failed to get chargeback costs for tag %s: %v", tagKey, err)
+				return nil, "", fmt.Errorf("failed to fetch chargeback costs for tag '%s': %v", tagKey, err)
+			}
+
+			for _, period := range result.ResultsByTime {
+				for _, group := range period.Groups {
+					metric := group.Metrics["UnblendedCost"]
+					cost, err := strconv.ParseFloat(aws.ToString(metric.Amount), 64)
+					if err != nil {
+						log.Printf("Failed to parse chargeback cost for keys %v: %v", group.Keys, err)
+						continue
+					}
+					if currency == "" {
+						currency = aws.ToString(metric.Unit)
+					}
+					rows = append(rows, chargeback.CostRow{
+						TagValue: strings.TrimPrefix(group.Keys[0], tagKey+"$"),
+						Service:  group.Keys[1],
+						Cost:     cost,
+						Credit:   credit,
+					})
+				}
+			}
+
+			if result.NextPageToken == nil {
+				break
+			}
+			input.NextPageToken = result.NextPageToken
+		}
+	}
+
+	return rows, currency, nil
+}
+
+// getChargebackRowsFromCUR sums ingested line items by tag value and service. Statements are
+// in the AWS billing currency; line items billed in other currencies, e.g. FOCUS imports from
+// other clouds, are summed per currency and converted at the rate for the month's last day.
+func getChargebackRowsFromCUR(ctx context.Context, cfg *config.Config, tagKey string, start, end time.Time) ([]chargeback.CostRow, string, error) {
+	if cfg.CURStore == nil || cfg.CURStore.Len() == 0 {
+		return nil, "", fmt.Errorf("no CUR data has been ingested")
+	}
+
+	costs := make(map[string]map[chargeback.CostRow]float64)
+	billingCurrency := ""
+	cfg.CURStore.Scan(start, end, func(item cur.LineItem) {
+		tagValue, _ := item.Tag(tagKey)
+		service := item.ServiceName
+		if service == "" {
+			service = item.ProductCode
+		}
+		itemCurrency := item.Currency
+		if itemCurrency == "" {
+			itemCurrency = "USD"
+		}
+		if item.Provider == "AWS" && billingCurrency == "" {
+			billingCurrency = itemCurrency
+		}
+		if costs[itemCurrency] == nil {
+			costs[itemCurrency] = make(map[chargeback.CostRow]float64)
+		}
+		credit := item.LineItemType == "Refund" || focus.ChargeCategory(item.LineItemType) == "Credit"
+		costs[itemCurrency][chargeback.CostRow{TagValue: tagValue, Service: service, Credit: credit}] += item.UnblendedCost
+	})
+
+	currencies := make([]string, 0, len(costs))
+	for itemCurrency := range costs {
+		currencies = append(currencies, itemCurrency)
+	}
+	sort.Strings(currencies)
+	if billingCurrency == "" && len(currencies) > 0 {
+		billingCurrency = currencies[0]
+	}
+
+	totals := make(map[chargeback.CostRow]float64)
+	for _, itemCurrency := range currencies {
+		rate := 1.0
+		if itemCurrency != billingCurrency {
+			if cfg.CurrencyRates == nil {
+				return nil, "", fmt.Errorf("CUR data is billed in both %s and %s; configure exchange rates to combine them", billingCurrency, itemCurrency)
+			}
+			exchangeRate, err := cfg.CurrencyRates.Rate(ctx, itemCurrency, billingCurrency, end.AddDate(0, 0, -1))
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to convert %s costs to %s: %v", itemCurrency, billingCurrency, err)
+			}
+			rate = exchangeRate.Rate
+		}
+		for row, cost := range costs[itemCurrency] {
+			totals[row] += cost * rate
+		}
+	}
+
+	rows := make([]chargeback.CostRow, 0, len(totals))
+	for row, cost := range totals {
+		row.Cost = cost
+		rows = append(rows, row)
+	}
+	return rows, billingCurrency, nil
+}
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/currency"
+)
+
+func TestChargebackRowsFromCURConvertsOtherCurrencies(t *testing.T) {
+	start := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	tags := map[string]string{"team": "payments"}
+	store, _ := cur.NewStore("")
+	store.Replace("cur", "1", []cur.LineItem{{Provider: "AWS", UsageStart: start, LineItemType: "Usage", ServiceName: "Amazon EC2", UnblendedCost: 2, Tags: tags}})
+	store.Replace("focus/azure.csv", "1", []cur.LineItem{{Provider: "Microsoft", UsageStart: start, LineItemType: "Usage", ServiceName: "Virtual Machines", UnblendedCost: 3, Currency: "EUR", Tags: tags}})
+
+	cfg := &config.Config{CURStore: store}
+	if _, _, err := getChargebackRowsFromCUR(context.Background(), cfg, "team", start, end); err == nil {
+		t.Errorf("expected an error combining USD and EUR without exchange rates")
+	}
+
+	cfg.CurrencyRates = currency.NewTable("stub", "USD", start, map[string]float64{"EUR": 0.5})
+	rows, billingCurrency, err := getChargebackRowsFromCUR(context.Background(), cfg, "team", start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if billingCurrency != "USD" {
+		t.Errorf("expected statements in the AWS billing currency USD, got %s", billingCurrency)
+	}
+	got := make(map[string]float64)
+	for _, row := range rows {
+		got[row.Service] += row.Cost
+	}
+	if len(got) != 2 || got["Amazon EC2"] != 2 || got["Virtual Machines"] != 6 {
+		t.Errorf("expected 2 USD for Amazon EC2 and 3 EUR converted to 6 USD for Virtual Machines, got %v", got)
+	}
+}
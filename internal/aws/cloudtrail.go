@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// cloudTrailLookbackDays is how far back CloudTrail event history can be searched.
+const cloudTrailLookbackDays = 90
+
+// hasCloudTrailEvent reports whether CloudTrail event history has one of the named events for a
+// resource since the given time, capped at cloudTrailLookbackDays. Other events for the resource,
+// such as the Describe calls made by this scan, are ignored.
+func hasCloudTrailEvent(ctx context.Context, client *cloudtrail.Client, resourceName string, eventNames map[string]bool, since time.Time) (bool, error) {
+	if earliest := time.Now().AddDate(0, 0, -cloudTrailLookbackDays); since.Before(earliest) {
+		since = earliest
+	}
+
+	paginator := cloudtrail.NewLookupEventsPaginator(client, &cloudtrail.LookupEventsInput{
+		LookupAttributes: []types.LookupAttribute{
+			{AttributeKey: types.LookupAttributeKeyResourceName, AttributeValue: aws.String(resourceName)},
+		},
+		StartTime: aws.Time(since),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, event := range page.Events {
+			if eventNames[aws.ToString(event.EventName)] {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
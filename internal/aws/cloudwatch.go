@@ -73,4 +73,89 @@ func isInstanceIdle(cfg *config.Config, instanceID string, start, end time.Time)
 
 	log.Printf("Instance %s is idle (<20%% CPU) from %s to %s with %d data points", instanceID, start.Format("2006-01-02"), end.Format("2006-01-02"), dataPoints)
 	return true, nil
-}
\ No newline at end of file
+}
+
+// metricQuery builds a GetMetricData query for one metric statistic at the given period in seconds.
+func metricQuery(id, namespace, metricName, stat string, dimensions []types.Dimension, period int32, returnData bool) types.MetricDataQuery {
+	return types.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &types.MetricStat{
+			Metric: &types.Metric{
+				Namespace:  aws.String(namespace),
+				MetricName: aws.String(metricName),
+				Dimensions: dimensions,
+			},
+			Period: aws.Int32(period),
+			Stat:   aws.String(stat),
+		},
+		ReturnData: aws.Bool(returnData),
+	}
+}
+
+// getMetricData runs the queries over all result pages and returns the values by query ID.
+func getMetricData(ctx context.Context, client *cloudwatch.Client, queries []types.MetricDataQuery, start, end time.Time) (map[string][]float64, error) {
+	values := make(map[string][]float64)
+	paginator := cloudwatch.NewGetMetricDataPaginator(client, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         &start,
+		EndTime:           &end,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page.MetricDataResults {
+			id := aws.ToString(result.Id)
+			values[id] = append(values[id], result.Values...)
+		}
+	}
+	return values, nil
+}
+
+// getMetricSeries runs the queries over all result pages and returns the values by query ID and
+// timestamp.
+func getMetricSeries(ctx context.Context, client *cloudwatch.Client, queries []types.MetricDataQuery, start, end time.Time) (map[string]map[time.Time]float64, error) {
+	series := make(map[string]map[time.Time]float64)
+	paginator := cloudwatch.NewGetMetricDataPaginator(client, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         &start,
+		EndTime:           &end,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page.MetricDataResults {
+			id := aws.ToString(result.Id)
+			if series[id] == nil {
+				series[id] = make(map[time.Time]float64)
+			}
+			for i, value := range result.Values {
+				series[id][result.Timestamps[i]] = value
+			}
+		}
+	}
+	return series, nil
+}
+
+// sumValues adds up metric values.
+func sumValues(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+// maxValue returns the largest metric value, or 0 when there are none.
+func maxValue(values []float64) float64 {
+	max := 0.0
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
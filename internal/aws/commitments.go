@@ -0,0 +1,253 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// GetReservationUtilization reports how much of the purchased Reserved Instance hours were used,
+// per period and in total.
+func GetReservationUtilization(ctx context.Context, cfg *config.Config, start, end time.Time, granularity types.Granularity) ([]models.ReservationUtilization, models.ReservationUtilization, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetReservationUtilizationInput{
+		TimePeriod:  commitmentPeriod(start, end),
+		Granularity: granularity,
+	}
+
+	periods := []models.ReservationUtilization{}
+	var total models.ReservationUtilization
+	for {
+		result, err := client.GetReservationUtilization(ctx, input)
+		if err != nil {
+			log.Printf("Failed to get reservation utilization: %v", err)
+			return nil, total, fmt.Errorf("failed to fetch reservation utilization: %v", err)
+		}
+		for _, period := range result.UtilizationsByTime {
+			periods = append(periods, toReservationUtilization(period.TimePeriod, period.Total))
+		}
+		if result.Total != nil {
+			total = toReservationUtilization(input.TimePeriod, result.Total)
+		}
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	log.Printf("Reservation utilization from %s to %s: %.2f%%", start.Format("2006-01-02"), end.Format("2006-01-02"), total.UtilizationPercentage)
+	return periods, total, nil
+}
+
+// GetSavingsPlansUtilization reports how much of the Savings Plans commitment was used, per period and in total.
+func GetSavingsPlansUtilization(ctx context.Context, cfg *config.Config, start, end time.Time, granularity types.Granularity) ([]models.SavingsPlansUtilization, models.SavingsPlansUtilization, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetSavingsPlansUtilizationInput{
+		TimePeriod:  commitmentPeriod(start, end),
+		Granularity: granularity,
+	}
+
+	var total models.SavingsPlansUtilization
+	result, err := client.GetSavingsPlansUtilization(ctx, input)
+	if err != nil {
+		log.Printf("Failed to get Savings Plans utilization: %v", err)
+		return nil, total, fmt.Errorf("failed to fetch Savings Plans utilization: %v", err)
+	}
+
+	periods := []models.SavingsPlansUtilization{}
+	for _, period := range result.SavingsPlansUtilizationsByTime {
+		periods = append(periods, toSavingsPlansUtilization(period.TimePeriod, period.Utilization, period.AmortizedCommitment, period.Savings))
+	}
+	if result.Total != nil {
+		total = toSavingsPlansUtilization(input.TimePeriod, result.Total.Utilization, result.Total.AmortizedCommitment, result.Total.Savings)
+	}
+
+	log.Printf("Savings Plans utilization from %s to %s: %.2f%%", start.Format("2006-01-02"), end.Format("2006-01-02"), total.UtilizationPercentage)
+	return periods, total, nil
+}
+
+// GetReservationCoverage reports how many running hours Reserved Instances covered, optionally
+// for one service (e.g. "Amazon Relational Database Service"; Cost Explorer defaults to EC2).
+func GetReservationCoverage(ctx context.Context, cfg *config.Config, start, end time.Time, granularity types.Granularity, service string) ([]models.ReservationCoverage, models.ReservationCoverage, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetReservationCoverageInput{
+		TimePeriod:  commitmentPeriod(start, end),
+		Granularity: granularity,
+	}
+	if service != "" {
+		input.Filter = &types.Expression{
+			Dimensions: &types.DimensionValues{
+				Key:    types.DimensionService,
+				Values: []string{service},
+			},
+		}
+	}
+
+	periods := []models.ReservationCoverage{}
+	var total models.ReservationCoverage
+	for {
+		result, err := client.GetReservationCoverage(ctx, input)
+		if err != nil {
+			log.Printf("Failed to get reservation coverage: %v", err)
+			return nil, total, fmt.Errorf("failed to fetch reservation coverage: %v", err)
+		}
+		for _, period := range result.CoveragesByTime {
+			periods = append(periods, toReservationCoverage(period.TimePeriod, period.Total))
+		}
+		if result.Total != nil {
+			total = toReservationCoverage(input.TimePeriod, result.Total)
+		}
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	return periods, total, nil
+}
+
+// GetSavingsPlansCoverage reports how much eligible spend Savings Plans covered, per period and
+// service, along with the overall total.
+func GetSavingsPlansCoverage(ctx context.Context, cfg *config.Config, start, end time.Time, granularity types.Granularity) ([]models.SavingsPlansCoverage, models.SavingsPlansCoverage, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetSavingsPlansCoverageInput{
+		TimePeriod:  commitmentPeriod(start, end),
+		Granularity: granularity,
+		GroupBy: []types.GroupDefinition{
+			{
+				Type: types.GroupDefinitionTypeDimension,
+				Key:  aws.String("SERVICE"),
+			},
+		},
+	}
+
+	periods := []models.SavingsPlansCoverage{}
+	total := models.SavingsPlansCoverage{Start: aws.ToString(input.TimePeriod.Start), End: aws.ToString(input.TimePeriod.End)}
+	for {
+		result, err := client.GetSavingsPlansCoverage(ctx, input)
+		if err != nil {
+			log.Printf("Failed to get Savings Plans coverage: %v", err)
+			return nil, total, fmt.Errorf("failed to fetch Savings Plans coverage: %v", err)
+		}
+		for _, coverage := range result.SavingsPlansCoverages {
+			period := toSavingsPlansCoverage(coverage)
+			periods = append(periods, period)
+			total.SpendCovered += period.SpendCovered
+			total.OnDemandCost += period.OnDemandCost
+			total.TotalCost += period.TotalCost
+		}
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	// Cost Explorer returns no overall total for Savings Plans coverage
+	if total.TotalCost > 0 {
+		total.CoveragePercentage = total.SpendCovered / total.TotalCost * 100
+	}
+	return periods, total, nil
+}
+
+// commitmentPeriod converts a date range to a Cost Explorer interval.
+func commitmentPeriod(start, end time.Time) *types.DateInterval {
+	return &types.DateInterval{
+		Start: aws.String(start.Format("2006-01-02")),
+		End:   aws.String(end.Format("2006-01-02")),
+	}
+}
+
+// parseCostExplorerAmount parses a Cost Explorer numeric string, treating missing values as zero.
+func parseCostExplorerAmount(value *string) float64 {
+	amount, err := strconv.ParseFloat(aws.ToString(value), 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+func toReservationUtilization(period *types.DateInterval, aggregates *types.ReservationAggregates) models.ReservationUtilization {
+	utilization := models.ReservationUtilization{}
+	if period != nil {
+		utilization.Start = aws.ToString(period.Start)
+		utilization.End = aws.ToString(period.End)
+	}
+	if aggregates == nil {
+		return utilization
+	}
+	utilization.UtilizationPercentage = parseCostExplorerAmount(aggregates.UtilizationPercentage)
+	utilization.PurchasedHours = parseCostExplorerAmount(aggregates.PurchasedHours)
+	utilization.UsedHours = parseCostExplorerAmount(aggregates.TotalActualHours)
+	utilization.UnusedHours = parseCostExplorerAmount(aggregates.UnusedHours)
+	utilization.AmortizedFee = parseCostExplorerAmount(aggregates.TotalAmortizedFee)
+	utilization.UnusedHoursCost = parseCostExplorerAmount(aggregates.RICostForUnusedHours)
+	utilization.OnDemandCostEquivalent = parseCostExplorerAmount(aggregates.OnDemandCostOfRIHoursUsed)
+	utilization.NetSavings = parseCostExplorerAmount(aggregates.NetRISavings)
+	return utilization
+}
+
+func toSavingsPlansUtilization(period *types.DateInterval, utilization *types.SavingsPlansUtilization, amortized *types.SavingsPlansAmortizedCommitment, savings *types.SavingsPlansSavings) models.SavingsPlansUtilization {
+	result := models.SavingsPlansUtilization{}
+	if period != nil {
+		result.Start = aws.ToString(period.Start)
+		result.End = aws.ToString(period.End)
+	}
+	if utilization != nil {
+		result.UtilizationPercentage = parseCostExplorerAmount(utilization.UtilizationPercentage)
+		result.TotalCommitment = parseCostExplorerAmount(utilization.TotalCommitment)
+		result.UsedCommitment = parseCostExplorerAmount(utilization.UsedCommitment)
+		result.UnusedCommitment = parseCostExplorerAmount(utilization.UnusedCommitment)
+	}
+	if amortized != nil {
+		result.AmortizedCommitment = parseCostExplorerAmount(amortized.TotalAmortizedCommitment)
+	}
+	if savings != nil {
+		result.OnDemandCostEquivalent = parseCostExplorerAmount(savings.OnDemandCostEquivalent)
+		result.NetSavings = parseCostExplorerAmount(savings.NetSavings)
+	}
+	return result
+}
+
+func toReservationCoverage(period *types.DateInterval, coverage *types.Coverage) models.ReservationCoverage {
+	result := models.ReservationCoverage{}
+	if period != nil {
+		result.Start = aws.ToString(period.Start)
+		result.End = aws.ToString(period.End)
+	}
+	if coverage == nil {
+		return result
+	}
+	if hours := coverage.CoverageHours; hours != nil {
+		result.CoverageHoursPercentage = parseCostExplorerAmount(hours.CoverageHoursPercentage)
+		result.ReservedHours = parseCostExplorerAmount(hours.ReservedHours)
+		result.OnDemandHours = parseCostExplorerAmount(hours.OnDemandHours)
+		result.TotalRunningHours = parseCostExplorerAmount(hours.TotalRunningHours)
+	}
+	if cost := coverage.CoverageCost; cost != nil {
+		result.OnDemandCost = parseCostExplorerAmount(cost.OnDemandCost)
+	}
+	return result
+}
+
+func toSavingsPlansCoverage(coverage types.SavingsPlansCoverage) models.SavingsPlansCoverage {
+	result := models.SavingsPlansCoverage{Attributes: coverage.Attributes}
+	if coverage.TimePeriod != nil {
+		result.Start = aws.ToString(coverage.TimePeriod.Start)
+		result.End = aws.ToString(coverage.TimePeriod.End)
+	}
+	if data := coverage.Coverage; data != nil {
+		result.CoveragePercentage = parseCostExplorerAmount(data.CoveragePercentage)
+		result.SpendCovered = parseCostExplorerAmount(data.SpendCoveredBySavingsPlans)
+		result.OnDemandCost = parseCostExplorerAmount(data.OnDemandCost)
+		result.TotalCost = parseCostExplorerAmount(data.TotalCost)
+	}
+	return result
+}
@@ -13,25 +13,39 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
 
-// GetTagCosts fetches costs by a specified tag key, aggregated over the date range.
-func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]models.TagCost, error) {
+// costExplorerMetrics maps cost metrics to Cost Explorer metric names.
+var costExplorerMetrics = map[cur.CostMetric]string{
+	cur.MetricUnblended:    "UnblendedCost",
+	cur.MetricAmortized:    "AmortizedCost",
+	cur.MetricNetAmortized: "NetAmortizedCost",
+}
+
+// GetTagCosts fetches costs by a specified tag key, aggregated over the date range. Amortized
+// metrics spread Reserved Instance and Savings Plans fees over the usage they cover, so
+// commitments are charged to the tag values that consume them.
+func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time, metric cur.CostMetric) ([]models.TagCost, error) {
+	metricName, ok := costExplorerMetrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported cost metric '%s'", metric)
+	}
+
 	// Initialize clients
 	client := costexplorer.NewFromConfig(cfg.AWSConfig)
 	iamClient := iam.NewFromConfig(cfg.AWSConfig)
 
 	// Validate tag is active in cost allocation tags
-	listTagsOutput, err := client.ListCostAllocationTags(context.TODO(), &costexplorer.ListCostAllocationTagsInput{})
+	allocationTags, err := ListCostAllocationTags(context.TODO(), cfg, []string{tagKey})
 	if err != nil {
-		log.Printf("Failed to list cost allocation tags: %v", err)
 		return nil, fmt.Errorf("failed to validate tag '%s': %v", tagKey, err)
 	}
 
 	isActive := false
-	for _, tag := range listTagsOutput.CostAllocationTags {
-		if aws.ToString(tag.TagKey) == tagKey {
+	for _, tag := range allocationTags {
+		if tag.TagKey == tagKey && tag.Status == string(types.CostAllocationTagStatusActive) {
 			isActive = true
 			log.Printf("Tag '%s' is active in cost allocation tags (Type: %s, Status: %s)", tagKey, tag.Type, tag.Status)
 			break
@@ -39,12 +53,13 @@ func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]mod
 	}
 	if !isActive {
 		log.Printf("Tag '%s' is not active in cost allocation tags", tagKey)
-		return nil, fmt.Errorf("tag '%s' is not active in cost allocation tags", tagKey)
+		return nil, fmt.Errorf("tag '%s' is not active in cost allocation tags, activate it via PUT /costs/allocation-tags/status", tagKey)
 	}
 
 	// Initialize map to aggregate costs by tag value
 	tagCostMap := make(map[string]struct {
 		TotalCost   float64
+		Currency    string
 		Resources   map[string]float64
 		CreatorName string
 	})
@@ -56,7 +71,7 @@ func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]mod
 			End:   aws.String(end.Format("2006-01-02")),
 		},
 		Granularity: types.GranularityDaily,
-		Metrics:     []string{"UnblendedCost"},
+		Metrics:     []string{metricName},
 		GroupBy: []types.GroupDefinition{
 			{
 				Type: types.GroupDefinitionTypeTag,
@@ -78,50 +93,11 @@ func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]mod
 	// Log raw response
 	log.Printf("Cost Explorer response for tag %s: %+v", tagKey, result)
 
-	// Resolve IAM names for aws:createdBy
-	getCreatorName := func(tagValue string) string {
-		if tagKey != "aws:createdBy" {
-			return ""
-		}
-		parts := strings.Split(tagValue, ":")
-		if len(parts) < 2 {
-			return tagValue
-		}
-		principalType, principalID := parts[0], parts[1]
-		switch principalType {
-		case "IAMUser":
-			users, err := iamClient.ListUsers(context.TODO(), &iam.ListUsersInput{})
-			if err != nil {
-				log.Printf("Failed to list IAM users for %s: %v", tagValue, err)
-				return tagValue
-			}
-			for _, user := range users.Users {
-				if aws.ToString(user.UserId) == principalID {
-					return aws.ToString(user.UserName)
-				}
-			}
-		case "AssumedRole":
-			roles, err := iamClient.ListRoles(context.TODO(), &iam.ListRolesInput{})
-			if err != nil {
-				log.Printf("Failed to list IAM roles for %s: %v", tagValue, err)
-				return tagValue
-			}
-			for _, role := range roles.Roles {
-				if aws.ToString(role.RoleId) == principalID {
-					return aws.ToString(role.RoleName)
-				}
-			}
-		case "Root":
-			return "Root Account"
-		}
-		return tagValue
-	}
-
 	// Aggregate costs
 	for _, group := range result.ResultsByTime {
-		for _, metric := range group.Groups {
-			tagValue := metric.Keys[0]
-			serviceName := metric.Keys[1]
+		for _, costGroup := range group.Groups {
+			tagValue := costGroup.Keys[0]
+			serviceName := costGroup.Keys[1]
 			// Clean tag value
 			if strings.HasPrefix(tagValue, tagKey+"$") {
 				log.Printf("Cleaning tag value %s to %s for tag %s", tagValue, strings.TrimPrefix(tagValue, tagKey+"$"), tagKey)
@@ -131,7 +107,7 @@ func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]mod
 				log.Printf("Skipping untagged or empty value for tag %s=%s, service %s", tagKey, tagValue, serviceName)
 				continue
 			}
-			costAmount, err := strconv.ParseFloat(aws.ToString(metric.Metrics["UnblendedCost"].Amount), 64)
+			costAmount, err := strconv.ParseFloat(aws.ToString(costGroup.Metrics[metricName].Amount), 64)
 			if err != nil {
 				log.Printf("Failed to parse cost for tag %s=%s, service %s: %v", tagKey, tagValue, serviceName, err)
 				continue
@@ -140,38 +116,53 @@ func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]mod
 			if !exists {
 				data = struct {
 					TotalCost   float64
+					Currency    string
 					Resources   map[string]float64
 					CreatorName string
-				}{Resources: make(map[string]float64)}
+				}{Currency: "USD", Resources: make(map[string]float64)}
 			}
 			data.TotalCost += costAmount
+			// Cost Explorer reports amounts in the payer account's billing currency, USD unless it says otherwise
+			if unit := aws.ToString(costGroup.Metrics[metricName].Unit); unit != "" {
+				data.Currency = unit
+			}
 			data.Resources[serviceName] += costAmount
-			data.CreatorName = getCreatorName(tagValue)
+			data.CreatorName = resolveCreatorName(iamClient, tagKey, tagValue)
 			tagCostMap[tagValue] = data
 			log.Printf("Aggregated cost for tag %s=%s, service %s on %s: %f", tagKey, tagValue, serviceName, *group.TimePeriod.Start, costAmount)
 		}
 	}
 
+	// Break costs down to individual resources where the data is available
+	tags := make(map[string]tagServiceCosts, len(tagCostMap))
+	for tagValue, data := range tagCostMap {
+		tags[tagValue] = tagServiceCosts{Currency: data.Currency, Services: data.Resources}
+	}
+	resourceCosts := getTagResourceCosts(context.TODO(), cfg, tagKey, tags, start, end, metric)
+
 	// Convert to slice
 	costs := []models.TagCost{}
 	for tagValue, data := range tagCostMap {
-		resources := []models.ResourceCost{}
-		for serviceName, cost := range data.Resources {
-			resources = append(resources, models.ResourceCost{
-				ResourceType: serviceName,
-				ResourceID:   "",
-				Cost:         cost,
-			})
+		resources, ok := resourceCosts[tagValue]
+		if !ok {
+			resources = []models.ResourceCost{}
+			for serviceName, cost := range data.Resources {
+				resources = append(resources, models.ResourceCost{
+					ResourceType: serviceName,
+					ResourceID:   "",
+					Cost:         cost,
+				})
+			}
 		}
 		costs = append(costs, models.TagCost{
 			TagKey:      tagKey,
 			TagValue:    tagValue,
 			Cost:        data.TotalCost,
-			Currency:    "USD",
+			Currency:    data.Currency,
 			Resources:   resources,
 			CreatorName: data.CreatorName,
 		})
-		log.Printf("Total cost for tag %s=%s: %f USD, Creator: %s", tagKey, tagValue, data.TotalCost, data.CreatorName)
+		log.Printf("Total cost for tag %s=%s: %f %s, Creator: %s", tagKey, tagValue, data.TotalCost, data.Currency, data.CreatorName)
 	}
 
 	if len(costs) == 0 {
@@ -179,4 +170,44 @@ func GetTagCosts(cfg *config.Config, tagKey string, start, end time.Time) ([]mod
 	}
 
 	return costs, nil
-}
\ No newline at end of file
+}
+
+// resolveCreatorName resolves an aws:createdBy tag value to an IAM user or role name.
+// It returns an empty string for other tag keys and the raw value when it cannot be resolved.
+func resolveCreatorName(iamClient *iam.Client, tagKey, tagValue string) string {
+	if tagKey != "aws:createdBy" {
+		return ""
+	}
+	parts := strings.Split(tagValue, ":")
+	if len(parts) < 2 {
+		return tagValue
+	}
+	principalType, principalID := parts[0], parts[1]
+	switch principalType {
+	case "IAMUser":
+		users, err := iamClient.ListUsers(context.TODO(), &iam.ListUsersInput{})
+		if err != nil {
+			log.Printf("Failed to list IAM users for %s: %v", tagValue, err)
+			return tagValue
+		}
+		for _, user := range users.Users {
+			if aws.ToString(user.UserId) == principalID {
+				return aws.ToString(user.UserName)
+			}
+		}
+	case "AssumedRole":
+		roles, err := iamClient.ListRoles(context.TODO(), &iam.ListRolesInput{})
+		if err != nil {
+			log.Printf("Failed to list IAM roles for %s: %v", tagValue, err)
+			return tagValue
+		}
+		for _, role := range roles.Roles {
+			if aws.ToString(role.RoleId) == principalID {
+				return aws.ToString(role.RoleName)
+			}
+		}
+	case "Root":
+		return "Root Account"
+	}
+	return tagValue
+}
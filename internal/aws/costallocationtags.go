@@ -0,0 +1,141 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// maxTagStatusUpdates is the number of tag keys UpdateCostAllocationTagsStatus accepts per call.
+const maxTagStatusUpdates = 20
+
+// ListCostAllocationTags fetches all cost allocation tags, optionally filtered by tag keys.
+func ListCostAllocationTags(ctx context.Context, cfg *config.Config, tagKeys []string) ([]models.CostAllocationTag, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	tags := []models.CostAllocationTag{}
+
+	input := &costexplorer.ListCostAllocationTagsInput{}
+	if len(tagKeys) > 0 {
+		input.TagKeys = tagKeys
+	}
+	paginator := costexplorer.NewListCostAllocationTagsPaginator(client, input)
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list cost allocation tags: %v", err)
+			return nil, fmt.Errorf("failed to list cost allocation tags: %v", err)
+		}
+
+		for _, tag := range page.CostAllocationTags {
+			tags = append(tags, models.CostAllocationTag{
+				TagKey:          aws.ToString(tag.TagKey),
+				Type:            string(tag.Type),
+				Status:          string(tag.Status),
+				LastUpdatedDate: aws.ToString(tag.LastUpdatedDate),
+				LastUsedDate:    aws.ToString(tag.LastUsedDate),
+			})
+		}
+	}
+
+	log.Printf("Found %d cost allocation tags", len(tags))
+	return tags, nil
+}
+
+// UpdateCostAllocationTagsStatus activates or deactivates cost allocation tags.
+// Per-tag failures reported by AWS are returned alongside a nil error.
+func UpdateCostAllocationTagsStatus(ctx context.Context, cfg *config.Config, updates []models.CostAllocationTagStatusUpdate) ([]models.CostAllocationTagUpdateError, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	updateErrors := []models.CostAllocationTagUpdateError{}
+
+	for i := 0; i < len(updates); i += maxTagStatusUpdates {
+		batch := updates[i:min(i+maxTagStatusUpdates, len(updates))]
+
+		entries := make([]types.CostAllocationTagStatusEntry, 0, len(batch))
+		for _, update := range batch {
+			entries = append(entries, types.CostAllocationTagStatusEntry{
+				TagKey: aws.String(update.TagKey),
+				Status: types.CostAllocationTagStatus(update.Status),
+			})
+		}
+
+		result, err := client.UpdateCostAllocationTagsStatus(ctx, &costexplorer.UpdateCostAllocationTagsStatusInput{
+			CostAllocationTagsStatus: entries,
+		})
+		if err != nil {
+			log.Printf("Failed to update cost allocation tag status: %v", err)
+			return nil, fmt.Errorf("failed to update cost allocation tag status: %v", err)
+		}
+
+		for _, updateErr := range result.Errors {
+			log.Printf("Failed to update status for tag %s: %s", aws.ToString(updateErr.TagKey), aws.ToString(updateErr.Message))
+			updateErrors = append(updateErrors, models.CostAllocationTagUpdateError{
+				TagKey:  aws.ToString(updateErr.TagKey),
+				Code:    aws.ToString(updateErr.Code),
+				Message: aws.ToString(updateErr.Message),
+			})
+		}
+	}
+
+	return updateErrors, nil
+}
+
+// StartCostAllocationTagBackfill requests that active cost allocation tags be applied to past costs.
+func StartCostAllocationTagBackfill(ctx context.Context, cfg *config.Config, backfillFrom time.Time) (*models.CostAllocationTagBackfill, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+
+	// Backfills must start on the first day of a month
+	from := time.Date(backfillFrom.Year(), backfillFrom.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	result, err := client.StartCostAllocationTagBackfill(ctx, &costexplorer.StartCostAllocationTagBackfillInput{
+		BackfillFrom: aws.String(from.Format(time.RFC3339)),
+	})
+	if err != nil {
+		log.Printf("Failed to start cost allocation tag backfill from %s: %v", from.Format("2006-01-02"), err)
+		return nil, fmt.Errorf("failed to start cost allocation tag backfill: %v", err)
+	}
+
+	log.Printf("Requested cost allocation tag backfill from %s", from.Format("2006-01-02"))
+	return convertBackfillRequest(result.BackfillRequest), nil
+}
+
+// ListCostAllocationTagBackfillHistory fetches past and in-progress backfill requests.
+func ListCostAllocationTagBackfillHistory(ctx context.Context, cfg *config.Config) ([]models.CostAllocationTagBackfill, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	backfills := []models.CostAllocationTagBackfill{}
+
+	paginator := costexplorer.NewListCostAllocationTagBackfillHistoryPaginator(client, &costexplorer.ListCostAllocationTagBackfillHistoryInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list cost allocation tag backfill history: %v", err)
+			return nil, fmt.Errorf("failed to list cost allocation tag backfill history: %v", err)
+		}
+		for i := range page.BackfillRequests {
+			backfills = append(backfills, *convertBackfillRequest(&page.BackfillRequests[i]))
+		}
+	}
+
+	return backfills, nil
+}
+
+// convertBackfillRequest converts a Cost Explorer backfill request to the API model.
+func convertBackfillRequest(req *types.CostAllocationTagBackfillRequest) *models.CostAllocationTagBackfill {
+	if req == nil {
+		return &models.CostAllocationTagBackfill{}
+	}
+	return &models.CostAllocationTagBackfill{
+		BackfillFrom:  aws.ToString(req.BackfillFrom),
+		Status:        string(req.BackfillStatus),
+		RequestedAt:   aws.ToString(req.RequestedAt),
+		CompletedAt:   aws.ToString(req.CompletedAt),
+		LastUpdatedAt: aws.ToString(req.LastUpdatedAt),
+	}
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// curIngestMu prevents overlapping CUR ingestion runs.
+var curIngestMu sync.Mutex
+
+// ErrCURIngestInProgress is returned when an ingestion run is already underway.
+var ErrCURIngestInProgress = fmt.Errorf("CUR ingestion already in progress")
+
+// curSources returns the CUR sources configured via CUR_DATA_DIR and CUR_S3_BUCKET.
+func curSources(cfg *config.Config) []cur.Source {
+	sources := []cur.Source{}
+	if cfg.CURDataDir != "" {
+		sources = append(sources, cur.DirSource{Dir: cfg.CURDataDir})
+	}
+	if cfg.CURS3Bucket != "" {
+		client := s3.NewFromConfig(cfg.AWSConfig, func(o *s3.Options) {
+			if cfg.CURS3Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.CURS3Endpoint)
+				o.UsePathStyle = true
+			}
+		})
+		sources = append(sources, cur.S3Source{Client: client, Bucket: cfg.CURS3Bucket, KeyPrefix: cfg.CURS3Prefix})
+	}
+	return sources
+}
+
+// IngestCUR loads new CUR files from every configured source into the CUR store.
+func IngestCUR(ctx context.Context, cfg *config.Config) ([]cur.IngestResult, error) {
+	if !curIngestMu.TryLock() {
+		return nil, ErrCURIngestInProgress
+	}
+	defer curIngestMu.Unlock()
+
+	sources := curSources(cfg)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no CUR source configured, set CUR_DATA_DIR or CUR_S3_BUCKET")
+	}
+
+	results := []cur.IngestResult{}
+	var ingestErr error
+	for _, source := range sources {
+		result, err := cur.Ingest(ctx, cfg.CURStore, source)
+		results = append(results, result)
+		if err != nil {
+			log.Printf("CUR ingestion from %s failed: %v", source.Prefix(), err)
+			ingestErr = fmt.Errorf("failed to ingest CUR data from %s: %v", source.Prefix(), err)
+			break
+		}
+		log.Printf("CUR ingestion from %s: %d files scanned, %d ingested, %d line items", source.Prefix(), result.FilesScanned, result.FilesIngested, result.LineItems)
+	}
+
+	// Persist once per run, keeping files ingested before a failure
+	if err := cfg.CURStore.Save(); err != nil {
+		log.Printf("Failed to save CUR store: %v", err)
+		if ingestErr == nil {
+			ingestErr = fmt.Errorf("failed to save CUR store: %v", err)
+		}
+	}
+	return results, ingestErr
+}
+
+// GetTagCostsFromCUR computes costs by tag key from ingested CUR data, without calling
+// Cost Explorer. The metric selects unblended or amortized cost.
+func GetTagCostsFromCUR(cfg *config.Config, tagKey string, start, end time.Time, metric cur.CostMetric) ([]models.TagCost, error) {
+	if cfg.CURStore == nil || cfg.CURStore.Len() == 0 {
+		return nil, fmt.Errorf("no CUR data has been ingested")
+	}
+
+	iamClient := iam.NewFromConfig(cfg.AWSConfig)
+	tagResources := cfg.CURStore.ResourceCostsByTag(tagKey, start, end, metric)
+
+	// One entry per tag value and currency, so costs in different currencies are never summed
+	costs := []models.TagCost{}
+	for tagValue, resources := range tagResources {
+		byCurrency := make(map[string]map[cur.ResourceKey]float64)
+		for key, cost := range resources {
+			if byCurrency[key.Currency] == nil {
+				byCurrency[key.Currency] = make(map[cur.ResourceKey]float64)
+			}
+			byCurrency[key.Currency][key] = cost
+		}
+
+		creatorName := resolveCreatorName(iamClient, tagKey, tagValue)
+		for currency, currencyResources := range byCurrency {
+			total := 0.0
+			for _, cost := range currencyResources {
+				total += cost
+			}
+			costs = append(costs, models.TagCost{
+				TagKey:      tagKey,
+				TagValue:    tagValue,
+				Cost:        total,
+				Currency:    currency,
+				Resources:   toResourceCosts(currencyResources),
+				CreatorName: creatorName,
+			})
+			log.Printf("Total %s CUR cost for tag %s=%s: %f %s", metric, tagKey, tagValue, total, currency)
+		}
+	}
+
+	if len(costs) == 0 {
+		log.Printf("No CUR costs found for tag %s from %s to %s", tagKey, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	return costs, nil
+}
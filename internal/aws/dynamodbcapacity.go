@@ -0,0 +1,338 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	aastypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/deepanshumishra/devcost-api/internal/rightsizing"
+)
+
+// DynamoDB recommendations saving less than this per month are not reported, as hourly averages
+// make smaller differences noise.
+const dynamoDBMinMonthlySavings = 1.0
+
+// dynamoDBCapacity is the capacity and hourly consumption of a table or global secondary index.
+type dynamoDBCapacity struct {
+	resourceType  string
+	resourceID    string // table name, or table/index for an index
+	provisioned   bool
+	readUnits     int64 // provisioned capacity units
+	writeUnits    int64
+	minReadUnits  int64 // auto scaling minimums, 0 without auto scaling
+	minWriteUnits int64
+	reads         []float64 // average consumption in units per second for each hour since start
+	writes        []float64
+	sizeBytes     int64
+}
+
+// onDemandMonthlyCost is what the consumption would cost in on-demand mode.
+func (c dynamoDBCapacity) onDemandMonthlyCost() float64 {
+	hours := float64(len(c.reads))
+	return (sumValues(c.reads)*dynamoDBReadRequestPrice + sumValues(c.writes)*dynamoDBWriteRequestPrice) * 3600 / hours * hoursPerMonth
+}
+
+// currentMonthlyCost is the cost of the current capacity. Auto scaled capacity is assumed to
+// follow consumption above its minimum.
+func (c dynamoDBCapacity) currentMonthlyCost() float64 {
+	if !c.provisioned {
+		return c.onDemandMonthlyCost()
+	}
+	readCost := float64(c.readUnits) * dynamoDBReadCapacityUnitHourlyPrice * hoursPerMonth
+	if c.minReadUnits > 0 {
+		readCost = autoScaledMonthlyCost(c.reads, c.minReadUnits, dynamoDBReadCapacityUnitHourlyPrice)
+	}
+	writeCost := float64(c.writeUnits) * dynamoDBWriteCapacityUnitHourlyPrice * hoursPerMonth
+	if c.minWriteUnits > 0 {
+		writeCost = autoScaledMonthlyCost(c.writes, c.minWriteUnits, dynamoDBWriteCapacityUnitHourlyPrice)
+	}
+	return readCost + writeCost
+}
+
+// rightSized returns the read and write units needed at peak and in quiet hours, and the monthly
+// cost of provisioned capacity auto scaled between them.
+func (c dynamoDBCapacity) rightSized() (peakReads, quietReads, peakWrites, quietWrites int64, monthlyCost float64) {
+	peakReads, quietReads, _ = rightsizing.ProvisionedUnits(c.reads)
+	peakWrites, quietWrites, _ = rightsizing.ProvisionedUnits(c.writes)
+	monthlyCost = autoScaledMonthlyCost(c.reads, quietReads, dynamoDBReadCapacityUnitHourlyPrice) +
+		autoScaledMonthlyCost(c.writes, quietWrites, dynamoDBWriteCapacityUnitHourlyPrice)
+	return peakReads, quietReads, peakWrites, quietWrites, monthlyCost
+}
+
+// autoScaledMonthlyCost estimates provisioned capacity that tracks hourly consumption at the auto
+// scaling target utilization without dropping below minUnits.
+func autoScaledMonthlyCost(consumedPerSecond []float64, minUnits int64, unitHourlyPrice float64) float64 {
+	units := 0.0
+	for _, consumed := range consumedPerSecond {
+		units += float64(max(minUnits, rightsizing.CapacityUnitsNeeded(consumed)))
+	}
+	return units / float64(len(consumedPerSecond)) * unitHourlyPrice * hoursPerMonth
+}
+
+// ListDynamoDBOptimizations compares each table's consumed capacity over the last 14 days with its
+// provisioned capacity. It recommends switching between on-demand and provisioned mode, lowering
+// provisioned capacity or auto scaling minimums, deleting global secondary indexes with no reads,
+// and moving tables whose storage outweighs their throughput to the Standard-IA table class.
+func ListDynamoDBOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	client := dynamodb.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	end := time.Now()
+	start := end.AddDate(0, 0, -14)
+	minimums := getDynamoDBAutoScalingMinimums(ctx, cfg)
+	optimizations := []models.Optimization{}
+
+	paginator := dynamodb.NewListTablesPaginator(client, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list DynamoDB tables: %v", err)
+			return nil, fmt.Errorf("failed to list DynamoDB tables: %v", err)
+		}
+
+		for _, tableName := range page.TableNames {
+			result, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+			if err != nil {
+				log.Printf("Failed to describe DynamoDB table %s: %v", tableName, err)
+				continue
+			}
+			capacities, err := getDynamoDBCapacities(ctx, cwClient, result.Table, minimums, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for DynamoDB table %s: %v", tableName, err)
+				continue
+			}
+			optimizations = append(optimizations, dynamoDBOptimizations(result.Table, capacities)...)
+		}
+	}
+
+	log.Printf("Found %d DynamoDB optimizations", len(optimizations))
+	return optimizations, nil
+}
+
+// dynamoDBOptimizations recommends changes for a table from the capacity of the table and its
+// global secondary indexes, in that order.
+func dynamoDBOptimizations(table *types.TableDescription, capacities []dynamoDBCapacity) []models.Optimization {
+	tableName := aws.ToString(table.TableName)
+	tableClass := types.TableClassStandard
+	if table.TableClassSummary != nil && table.TableClassSummary.TableClass != "" {
+		tableClass = table.TableClassSummary.TableClass
+	}
+	storagePrice := dynamoDBStandardPerGBMonthPrice
+	if tableClass == types.TableClassStandardInfrequentAccess {
+		storagePrice = dynamoDBStandardIAPerGBMonthPrice
+	}
+	optimizations := []models.Optimization{}
+
+	// Indexes nobody reads are deleted rather than resized
+	used := []dynamoDBCapacity{}
+	for _, capacity := range capacities {
+		if capacity.resourceType != "dynamodb:index" || sumValues(capacity.reads) > 0 {
+			used = append(used, capacity)
+			continue
+		}
+		savings := capacity.currentMonthlyCost() + float64(capacity.sizeBytes)/bytesPerGB*storagePrice
+		if savings < dynamoDBMinMonthlySavings {
+			continue
+		}
+		optimizations = append(optimizations, models.Optimization{
+			Category:                 "unused-index",
+			ResourceType:             capacity.resourceType,
+			ResourceID:               capacity.resourceID,
+			CurrentConfiguration:     fmt.Sprintf("%.1f GB, $%.2f/month in throughput", float64(capacity.sizeBytes)/bytesPerGB, capacity.currentMonthlyCost()),
+			RecommendedConfiguration: "delete index",
+			Reason:                   fmt.Sprintf("No reads for %d days; every table write is still replicated to it", len(capacity.reads)/24),
+			EstimatedMonthlySavings:  savings,
+		})
+	}
+
+	current, onDemand, autoScaled := 0.0, 0.0, 0.0
+	storageBytes := int64(0)
+	for _, capacity := range used {
+		_, _, _, _, cost := capacity.rightSized()
+		current += capacity.currentMonthlyCost()
+		onDemand += capacity.onDemandMonthlyCost()
+		autoScaled += cost
+		storageBytes += capacity.sizeBytes
+	}
+
+	switch {
+	case !used[0].provisioned:
+		if onDemand-autoScaled >= dynamoDBMinMonthlySavings {
+			peakReads, quietReads, peakWrites, quietWrites, _ := used[0].rightSized()
+			optimizations = append(optimizations, models.Optimization{
+				Category:                 "capacity-mode",
+				ResourceType:             "dynamodb:table",
+				ResourceID:               tableName,
+				CurrentConfiguration:     fmt.Sprintf("on-demand, $%.2f/month", onDemand),
+				RecommendedConfiguration: fmt.Sprintf("provisioned with auto scaling, %d-%d RCU and %d-%d WCU", quietReads, peakReads, quietWrites, peakWrites),
+				Reason:                   fmt.Sprintf("Steady traffic costs $%.2f/month as auto scaled provisioned capacity", autoScaled),
+				EstimatedMonthlySavings:  onDemand - autoScaled,
+			})
+		}
+	case onDemand < autoScaled && current-onDemand >= dynamoDBMinMonthlySavings:
+		optimizations = append(optimizations, models.Optimization{
+			Category:                 "capacity-mode",
+			ResourceType:             "dynamodb:table",
+			ResourceID:               tableName,
+			CurrentConfiguration:     fmt.Sprintf("provisioned, $%.2f/month", current),
+			RecommendedConfiguration: "on-demand",
+			Reason:                   fmt.Sprintf("Traffic is too low or spiky for provisioned capacity; on-demand costs $%.2f/month", onDemand),
+			EstimatedMonthlySavings:  current - onDemand,
+		})
+	default:
+		for _, capacity := range used {
+			if optimization, ok := dynamoDBCapacityOptimization(capacity); ok {
+				optimizations = append(optimizations, optimization)
+			}
+		}
+	}
+
+	// Standard-IA storage is 60% cheaper, throughput 25% dearer
+	if tableClass == types.TableClassStandard {
+		storageGB := float64(storageBytes) / bytesPerGB
+		savings := storageGB*(dynamoDBStandardPerGBMonthPrice-dynamoDBStandardIAPerGBMonthPrice) - current*dynamoDBStandardIAThroughputPremium
+		if savings >= dynamoDBMinMonthlySavings {
+			optimizations = append(optimizations, models.Optimization{
+				Category:                 "table-class",
+				ResourceType:             "dynamodb:table",
+				ResourceID:               tableName,
+				CurrentConfiguration:     fmt.Sprintf("STANDARD, %.1f GB, $%.2f/month in throughput", storageGB, current),
+				RecommendedConfiguration: string(types.TableClassStandardInfrequentAccess),
+				Reason:                   "Storage costs outweigh throughput",
+				EstimatedMonthlySavings:  savings,
+			})
+		}
+	}
+
+	return optimizations
+}
+
+// dynamoDBCapacityOptimization recommends auto scaling, or lower auto scaling minimums, for a
+// provisioned table or index whose capacity exceeds its consumption.
+func dynamoDBCapacityOptimization(capacity dynamoDBCapacity) (models.Optimization, bool) {
+	peakReads, quietReads, peakWrites, quietWrites, cost := capacity.rightSized()
+	current := capacity.currentMonthlyCost()
+	if current-cost < dynamoDBMinMonthlySavings {
+		return models.Optimization{}, false
+	}
+
+	optimization := models.Optimization{
+		Category:                 "capacity",
+		ResourceType:             capacity.resourceType,
+		ResourceID:               capacity.resourceID,
+		CurrentConfiguration:     fmt.Sprintf("%d RCU, %d WCU", capacity.readUnits, capacity.writeUnits),
+		RecommendedConfiguration: fmt.Sprintf("auto scaling, %d-%d RCU and %d-%d WCU", quietReads, peakReads, quietWrites, peakWrites),
+		Reason:                   fmt.Sprintf("Consumption peaks at %d RCU and %d WCU at %.0f%% utilization", peakReads, peakWrites, rightsizing.DynamoDBTargetUtilization),
+		EstimatedMonthlySavings:  current - cost,
+	}
+	if capacity.minReadUnits > 0 || capacity.minWriteUnits > 0 {
+		optimization.CurrentConfiguration = fmt.Sprintf("auto scaling minimum %d RCU, %d WCU", capacity.minReadUnits, capacity.minWriteUnits)
+		optimization.RecommendedConfiguration = fmt.Sprintf("auto scaling minimum %d RCU, %d WCU", quietReads, quietWrites)
+		optimization.Reason = fmt.Sprintf("Quiet hours need %d RCU and %d WCU", quietReads, quietWrites)
+	}
+	return optimization, true
+}
+
+// getDynamoDBCapacities reads the hourly consumed capacity of a table and its global secondary
+// indexes between start and end. Hours without data points had no consumption.
+func getDynamoDBCapacities(ctx context.Context, client *cloudwatch.Client, table *types.TableDescription, minimums map[string]int64, start, end time.Time) ([]dynamoDBCapacity, error) {
+	tableName := aws.ToString(table.TableName)
+	provisioned := table.BillingModeSummary == nil || table.BillingModeSummary.BillingMode != types.BillingModePayPerRequest
+
+	tableCapacity := dynamoDBCapacity{
+		resourceType:  "dynamodb:table",
+		resourceID:    tableName,
+		provisioned:   provisioned,
+		minReadUnits:  minimums["table/"+tableName+"|"+string(aastypes.ScalableDimensionDynamoDBTableReadCapacityUnits)],
+		minWriteUnits: minimums["table/"+tableName+"|"+string(aastypes.ScalableDimensionDynamoDBTableWriteCapacityUnits)],
+		sizeBytes:     aws.ToInt64(table.TableSizeBytes),
+	}
+	if table.ProvisionedThroughput != nil {
+		tableCapacity.readUnits = aws.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits)
+		tableCapacity.writeUnits = aws.ToInt64(table.ProvisionedThroughput.WriteCapacityUnits)
+	}
+	capacities := []dynamoDBCapacity{tableCapacity}
+	dimensions := [][]cwtypes.Dimension{{{Name: aws.String("TableName"), Value: aws.String(tableName)}}}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		indexName := aws.ToString(index.IndexName)
+		resourceID := "table/" + tableName + "/index/" + indexName
+		indexCapacity := dynamoDBCapacity{
+			resourceType:  "dynamodb:index",
+			resourceID:    tableName + "/" + indexName,
+			provisioned:   provisioned,
+			minReadUnits:  minimums[resourceID+"|"+string(aastypes.ScalableDimensionDynamoDBIndexReadCapacityUnits)],
+			minWriteUnits: minimums[resourceID+"|"+string(aastypes.ScalableDimensionDynamoDBIndexWriteCapacityUnits)],
+			sizeBytes:     aws.ToInt64(index.IndexSizeBytes),
+		}
+		if index.ProvisionedThroughput != nil {
+			indexCapacity.readUnits = aws.ToInt64(index.ProvisionedThroughput.ReadCapacityUnits)
+			indexCapacity.writeUnits = aws.ToInt64(index.ProvisionedThroughput.WriteCapacityUnits)
+		}
+		capacities = append(capacities, indexCapacity)
+		dimensions = append(dimensions, []cwtypes.Dimension{
+			{Name: aws.String("TableName"), Value: aws.String(tableName)},
+			{Name: aws.String("GlobalSecondaryIndexName"), Value: aws.String(indexName)},
+		})
+	}
+
+	queries := []cwtypes.MetricDataQuery{}
+	for i := range capacities {
+		queries = append(queries,
+			metricQuery(fmt.Sprintf("reads%d", i), "AWS/DynamoDB", "ConsumedReadCapacityUnits", "Sum", dimensions[i], 3600, true),
+			metricQuery(fmt.Sprintf("writes%d", i), "AWS/DynamoDB", "ConsumedWriteCapacityUnits", "Sum", dimensions[i], 3600, true),
+		)
+	}
+	series, err := getMetricSeries(ctx, client, queries, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Place each hourly sum at its hour since start; hours without a data point stay zero
+	hours := int(end.Sub(start).Hours())
+	perSecond := func(sums map[time.Time]float64) []float64 {
+		consumed := make([]float64, hours)
+		for timestamp, sum := range sums {
+			if hour := int(timestamp.Sub(start).Hours()); hour >= 0 && hour < hours {
+				consumed[hour] = sum / 3600
+			}
+		}
+		return consumed
+	}
+	for i := range capacities {
+		capacities[i].reads = perSecond(series[fmt.Sprintf("reads%d", i)])
+		capacities[i].writes = perSecond(series[fmt.Sprintf("writes%d", i)])
+	}
+	return capacities, nil
+}
+
+// getDynamoDBAutoScalingMinimums returns the minimum capacity of each DynamoDB auto scaling target,
+// keyed by resource ID and scalable dimension, e.g. "table/orders|dynamodb:table:ReadCapacityUnits".
+func getDynamoDBAutoScalingMinimums(ctx context.Context, cfg *config.Config) map[string]int64 {
+	client := applicationautoscaling.NewFromConfig(cfg.AWSConfig)
+	minimums := make(map[string]int64)
+
+	paginator := applicationautoscaling.NewDescribeScalableTargetsPaginator(client, &applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace: aastypes.ServiceNamespaceDynamodb,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe DynamoDB auto scaling targets: %v", err)
+			return minimums
+		}
+		for _, target := range page.ScalableTargets {
+			minimums[aws.ToString(target.ResourceId)+"|"+string(target.ScalableDimension)] = int64(aws.ToInt32(target.MinCapacity))
+		}
+	}
+	return minimums
+}
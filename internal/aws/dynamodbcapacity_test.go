@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// steady returns two weeks of hourly consumption at a constant rate in units per second.
+func steady(perSecond float64) []float64 {
+	hours := make([]float64, 14*24)
+	for i := range hours {
+		hours[i] = perSecond
+	}
+	return hours
+}
+
+func TestDynamoDBOptimizations(t *testing.T) {
+	table := func(name string, capacity dynamoDBCapacity) dynamoDBCapacity {
+		capacity.resourceType, capacity.resourceID = "dynamodb:table", name
+		return capacity
+	}
+	index := func(name string, capacity dynamoDBCapacity) dynamoDBCapacity {
+		capacity.resourceType, capacity.resourceID = "dynamodb:index", name
+		return capacity
+	}
+
+	tests := []struct {
+		name       string
+		tableClass types.TableClass
+		capacities []dynamoDBCapacity
+		want       []string // category:resource ID
+	}{
+		// 100 reads/s on demand cost ~$33/month against ~$14 auto scaled
+		{
+			name:       "steady on-demand table moves to provisioned",
+			capacities: []dynamoDBCapacity{table("orders", dynamoDBCapacity{reads: steady(100), writes: steady(1)})},
+			want:       []string{"capacity-mode:orders"},
+		},
+		{
+			name: "idle provisioned table moves to on-demand",
+			capacities: []dynamoDBCapacity{table("orders", dynamoDBCapacity{
+				provisioned: true, readUnits: 1000, writeUnits: 1000, reads: steady(0.01), writes: steady(0.01),
+			})},
+			want: []string{"capacity-mode:orders"},
+		},
+		{
+			name: "over-provisioned steady table gets auto scaling",
+			capacities: []dynamoDBCapacity{table("orders", dynamoDBCapacity{
+				provisioned: true, readUnits: 1000, writeUnits: 100, reads: steady(100), writes: steady(10),
+			})},
+			want: []string{"capacity:orders"},
+		},
+		{
+			name: "high auto scaling minimum is lowered",
+			capacities: []dynamoDBCapacity{table("orders", dynamoDBCapacity{
+				provisioned: true, readUnits: 500, writeUnits: 50, minReadUnits: 500, minWriteUnits: 50, reads: steady(100), writes: steady(10),
+			})},
+			want: []string{"capacity:orders"},
+		},
+		{
+			name: "right-sized table has no findings",
+			capacities: []dynamoDBCapacity{table("orders", dynamoDBCapacity{
+				provisioned: true, readUnits: 143, writeUnits: 15, minReadUnits: 143, minWriteUnits: 15, reads: steady(100), writes: steady(10),
+			})},
+		},
+		{
+			name: "index with no reads is deleted",
+			capacities: []dynamoDBCapacity{
+				table("orders", dynamoDBCapacity{provisioned: true, readUnits: 143, writeUnits: 15, minReadUnits: 143, minWriteUnits: 15, reads: steady(100), writes: steady(10)}),
+				index("orders/by-customer", dynamoDBCapacity{provisioned: true, readUnits: 100, writeUnits: 100, reads: steady(0), writes: steady(10)}),
+			},
+			want: []string{"unused-index:orders/by-customer"},
+		},
+		{
+			name: "storage-heavy table moves to Standard-IA",
+			capacities: []dynamoDBCapacity{table("archive", dynamoDBCapacity{
+				provisioned: true, readUnits: 1, writeUnits: 1, minReadUnits: 1, minWriteUnits: 1, reads: steady(0.1), writes: steady(0.1), sizeBytes: 1000 * bytesPerGB,
+			})},
+			want: []string{"table-class:archive"},
+		},
+		{
+			name:       "Standard-IA table is not moved again",
+			tableClass: types.TableClassStandardInfrequentAccess,
+			capacities: []dynamoDBCapacity{table("archive", dynamoDBCapacity{
+				provisioned: true, readUnits: 1, writeUnits: 1, minReadUnits: 1, minWriteUnits: 1, reads: steady(0.1), writes: steady(0.1), sizeBytes: 1000 * bytesPerGB,
+			})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description := &types.TableDescription{TableName: aws.String(tt.capacities[0].resourceID)}
+			if tt.tableClass != "" {
+				description.TableClassSummary = &types.TableClassSummary{TableClass: tt.tableClass}
+			}
+
+			got := []string{}
+			for _, optimization := range dynamoDBOptimizations(description, tt.capacities) {
+				if optimization.EstimatedMonthlySavings < dynamoDBMinMonthlySavings {
+					t.Errorf("%s for %s saves only %.2f", optimization.Category, optimization.ResourceID, optimization.EstimatedMonthlySavings)
+				}
+				got = append(got, optimization.Category+":"+optimization.ResourceID)
+			}
+			sort.Strings(got)
+			want := append([]string{}, tt.want...)
+			sort.Strings(want)
+			if len(got) != len(want) {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("expected %v, got %v", want, got)
+				}
+			}
+		})
+	}
+}
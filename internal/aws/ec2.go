@@ -12,8 +12,9 @@ import (
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
 
-// ListUnusedEC2Resources identifies unused EC2 instances, EBS volumes, and Elastic IPs.
-func ListUnusedEC2Resources(cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+// ListUnusedEC2Resources identifies unused EC2 instances, EBS volumes, and Elastic IPs. Instances
+// in groupedInstances were already judged with their Auto Scaling group and are skipped.
+func ListUnusedEC2Resources(cfg *config.Config, start, end time.Time, groupedInstances map[string]bool) ([]models.UnusedResource, error) {
 	// Initialize EC2 client
 	client := ec2.NewFromConfig(cfg.AWSConfig)
 
@@ -29,11 +30,18 @@ func ListUnusedEC2Resources(cfg *config.Config, start, end time.Time) ([]models.
 	}
 
 	// Check for idle EC2 instances
+	stoppedInstances := make(map[string]bool)
 	for _, reservation := range ec2Result.Reservations {
 		for _, instance := range reservation.Instances {
+			if instance.State.Name == types.InstanceStateNameStopped {
+				stoppedInstances[aws.ToString(instance.InstanceId)] = true
+			}
 			if instance.State.Name != types.InstanceStateNameRunning {
 				continue
 			}
+			if groupedInstances[aws.ToString(instance.InstanceId)] {
+				continue
+			}
 			isIdle, err := isInstanceIdle(cfg, aws.ToString(instance.InstanceId), start, end)
 			if err != nil {
 				log.Printf("Failed to check idle status for instance %s: %v", aws.ToString(instance.InstanceId), err)
@@ -78,17 +86,24 @@ func ListUnusedEC2Resources(cfg *config.Config, start, end time.Time) ([]models.
 		return unusedResources, err
 	}
 
-	// Check for unassociated Elastic IPs
+	// Check for Elastic IPs that are unassociated or on stopped instances, both billed as public IPv4
 	for _, address := range eipResult.Addresses {
+		reason := ""
 		if address.AssociationId == nil {
+			reason = "Not associated with any resource"
+		} else if stoppedInstances[aws.ToString(address.InstanceId)] {
+			reason = "Associated with stopped instance " + aws.ToString(address.InstanceId)
+		}
+		if reason != "" {
 			unusedResources = append(unusedResources, models.UnusedResource{
-				ResourceType: "ec2:elastic-ip",
-				ResourceID:   aws.ToString(address.AllocationId),
-				Reason:       "Not associated with any resource",
+				ResourceType:         "ec2:elastic-ip",
+				ResourceID:           aws.ToString(address.AllocationId),
+				Reason:               reason,
+				EstimatedMonthlyCost: publicIPv4HourlyPrice * hoursPerMonth,
 			})
 			log.Printf("Found unused Elastic IP: %s", aws.ToString(address.AllocationId))
 		}
 	}
 
 	return unusedResources, nil
-}
\ No newline at end of file
+}
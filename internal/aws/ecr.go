@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ecrMinWastedBytes is the untagged and unpulled image size above which a repository is reported.
+const ecrMinWastedBytes = 1024 * 1024 * 1024
+
+// ecrIndexMediaTypes are the manifest media types of multi-architecture image indexes.
+var ecrIndexMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// ListUnusedECRImages identifies ECR repositories holding at least 1 GiB of untagged images or of
+// images pushed more than unusedForDays ago and not pulled since. Untagged images referenced by a
+// multi-architecture index are its per-platform manifests, not waste.
+func ListUnusedECRImages(ctx context.Context, cfg *config.Config, unusedForDays int) ([]models.UnusedResource, error) {
+	client := ecr.NewFromConfig(cfg.AWSConfig)
+	unusedRepositories := []models.UnusedResource{}
+	cutoff := time.Now().AddDate(0, 0, -unusedForDays)
+
+	paginator := ecr.NewDescribeRepositoriesPaginator(client, &ecr.DescribeRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe ECR repositories: %v", err)
+			return nil, fmt.Errorf("failed to describe ECR repositories: %v", err)
+		}
+
+		for _, repository := range page.Repositories {
+			repositoryName := aws.ToString(repository.RepositoryName)
+			images, err := describeECRImages(ctx, client, repository.RepositoryName)
+			if err != nil {
+				log.Printf("Failed to describe images in ECR repository %s: %v", repositoryName, err)
+				continue
+			}
+			// Without the index contents an untagged image may belong to a tagged index
+			indexChildren, err := getECRIndexChildren(ctx, client, repository.RepositoryName, images)
+			if err != nil {
+				log.Printf("Failed to read image indexes in ECR repository %s, skipping untagged images: %v", repositoryName, err)
+			}
+
+			var untaggedBytes, unpulledBytes int64
+			untagged, unpulled := 0, 0
+			for _, image := range images {
+				size := aws.ToInt64(image.ImageSizeInBytes)
+				switch {
+				case len(image.ImageTags) == 0:
+					if indexChildren == nil || indexChildren[aws.ToString(image.ImageDigest)] {
+						continue
+					}
+					untagged++
+					untaggedBytes += size
+				case (image.LastRecordedPullTime == nil || image.LastRecordedPullTime.Before(cutoff)) && image.ImagePushedAt != nil && image.ImagePushedAt.Before(cutoff):
+					unpulled++
+					unpulledBytes += size
+				}
+			}
+
+			if untaggedBytes+unpulledBytes < ecrMinWastedBytes {
+				continue
+			}
+			unusedRepositories = append(unusedRepositories, models.UnusedResource{
+				ResourceType: "ecr:repository",
+				ResourceID:   repositoryName,
+				Reason: fmt.Sprintf("%d untagged images (%.1f GiB) and %d images not pulled in %d days (%.1f GiB)",
+					untagged, float64(untaggedBytes)/bytesPerGB, unpulled, unusedForDays, float64(unpulledBytes)/bytesPerGB),
+				EstimatedMonthlyCost: float64(untaggedBytes+unpulledBytes) / bytesPerGB * ecrPerGBMonthPrice,
+			})
+			log.Printf("Found ECR repository with unused images: %s", repositoryName)
+		}
+	}
+
+	return unusedRepositories, nil
+}
+
+// describeECRImages lists every image in a repository.
+func describeECRImages(ctx context.Context, client *ecr.Client, repositoryName *string) ([]types.ImageDetail, error) {
+	images := []types.ImageDetail{}
+	paginator := ecr.NewDescribeImagesPaginator(client, &ecr.DescribeImagesInput{RepositoryName: repositoryName})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, page.ImageDetails...)
+	}
+	return images, nil
+}
+
+// getECRIndexChildren returns the digests of the manifests referenced by the image indexes
+// among images. BatchGetImage accepts at most 100 images per request.
+func getECRIndexChildren(ctx context.Context, client *ecr.Client, repositoryName *string, images []types.ImageDetail) (map[string]bool, error) {
+	indexIDs := []types.ImageIdentifier{}
+	for _, image := range images {
+		if slices.Contains(ecrIndexMediaTypes, aws.ToString(image.ImageManifestMediaType)) {
+			indexIDs = append(indexIDs, types.ImageIdentifier{ImageDigest: image.ImageDigest})
+		}
+	}
+
+	children := make(map[string]bool)
+	for batchStart := 0; batchStart < len(indexIDs); batchStart += 100 {
+		result, err := client.BatchGetImage(ctx, &ecr.BatchGetImageInput{
+			RepositoryName:     repositoryName,
+			ImageIds:           indexIDs[batchStart:min(batchStart+100, len(indexIDs))],
+			AcceptedMediaTypes: ecrIndexMediaTypes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Failures) > 0 {
+			return nil, fmt.Errorf("failed to get %d image indexes: %s", len(result.Failures), aws.ToString(result.Failures[0].FailureReason))
+		}
+		for _, image := range result.Images {
+			var index struct {
+				Manifests []struct {
+					Digest string `json:"digest"`
+				} `json:"manifests"`
+			}
+			if err := json.Unmarshal([]byte(aws.ToString(image.ImageManifest)), &index); err != nil {
+				return nil, fmt.Errorf("failed to parse image index: %v", err)
+			}
+			for _, manifest := range index.Manifests {
+				children[manifest.Digest] = true
+			}
+		}
+	}
+	return children, nil
+}
@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// Most clusters and services the ECS describe calls accept per call.
+const (
+	ecsDescribeClustersBatch = 100
+	ecsDescribeServicesBatch = 10
+)
+
+// ListUnusedECSResources identifies ECS clusters holding EC2 capacity with no tasks, and services
+// scaled to zero that are still attached to load balancer target groups.
+func ListUnusedECSResources(ctx context.Context, cfg *config.Config) ([]models.UnusedResource, error) {
+	client := ecs.NewFromConfig(cfg.AWSConfig)
+	unusedResources := []models.UnusedResource{}
+
+	clusterArns := []string{}
+	paginator := ecs.NewListClustersPaginator(client, &ecs.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list ECS clusters: %v", err)
+			return nil, fmt.Errorf("failed to list ECS clusters: %v", err)
+		}
+		clusterArns = append(clusterArns, page.ClusterArns...)
+	}
+
+	clusters := []types.Cluster{}
+	for i := 0; i < len(clusterArns); i += ecsDescribeClustersBatch {
+		result, err := client.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+			Clusters: clusterArns[i:min(i+ecsDescribeClustersBatch, len(clusterArns))],
+		})
+		if err != nil {
+			log.Printf("Failed to describe ECS clusters: %v", err)
+			return nil, fmt.Errorf("failed to describe ECS clusters: %v", err)
+		}
+		clusters = append(clusters, result.Clusters...)
+	}
+
+	for _, cluster := range clusters {
+		clusterArn := aws.ToString(cluster.ClusterArn)
+
+		// Fargate capacity costs nothing without tasks; EC2 capacity keeps running
+		ec2Providers := 0
+		for _, provider := range cluster.CapacityProviders {
+			if provider != "FARGATE" && provider != "FARGATE_SPOT" {
+				ec2Providers++
+			}
+		}
+		if cluster.RunningTasksCount == 0 && cluster.PendingTasksCount == 0 && (ec2Providers > 0 || cluster.RegisteredContainerInstancesCount > 0) {
+			unusedResources = append(unusedResources, models.UnusedResource{
+				ResourceType: "ecs:cluster",
+				ResourceID:   clusterArn,
+				Reason: fmt.Sprintf("No running tasks but %d container instances and %d EC2 capacity providers",
+					cluster.RegisteredContainerInstancesCount, ec2Providers),
+			})
+			log.Printf("Found unused ECS cluster: %s", clusterArn)
+		}
+
+		services, err := listIdleECSServices(ctx, client, clusterArn)
+		if err != nil {
+			log.Printf("Failed to check services in ECS cluster %s: %v", clusterArn, err)
+			continue
+		}
+		unusedResources = append(unusedResources, services...)
+	}
+
+	return unusedResources, nil
+}
+
+// listIdleECSServices finds services in a cluster with a desired count of zero that still have
+// load balancers configured.
+func listIdleECSServices(ctx context.Context, client *ecs.Client, clusterArn string) ([]models.UnusedResource, error) {
+	idleServices := []models.UnusedResource{}
+
+	serviceArns := []string{}
+	paginator := ecs.NewListServicesPaginator(client, &ecs.ListServicesInput{Cluster: aws.String(clusterArn)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		serviceArns = append(serviceArns, page.ServiceArns...)
+	}
+
+	for i := 0; i < len(serviceArns); i += ecsDescribeServicesBatch {
+		result, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterArn),
+			Services: serviceArns[i:min(i+ecsDescribeServicesBatch, len(serviceArns))],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range result.Services {
+			if service.DesiredCount != 0 || len(service.LoadBalancers) == 0 {
+				continue
+			}
+			idleServices = append(idleServices, models.UnusedResource{
+				ResourceType: "ecs:service",
+				ResourceID:   aws.ToString(service.ServiceArn),
+				Reason:       fmt.Sprintf("Desired count 0 but still attached to %d load balancer target groups", len(service.LoadBalancers)),
+			})
+			log.Printf("Found idle ECS service: %s", aws.ToString(service.ServiceArn))
+		}
+	}
+
+	return idleServices, nil
+}
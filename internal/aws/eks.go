@@ -0,0 +1,191 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// eksIdleNodeCPU is the hourly average CPU (%) a node group must never exceed to be reported idle.
+const eksIdleNodeCPU = 5.0
+
+// eksSystemNamespaces hold cluster add-ons rather than workloads.
+var eksSystemNamespaces = map[string]bool{
+	"kube-system":       true,
+	"kube-public":       true,
+	"kube-node-lease":   true,
+	"amazon-cloudwatch": true,
+	"amazon-guardduty":  true,
+	"aws-observability": true,
+}
+
+// ListUnusedEKSResources identifies EKS clusters running no pods outside system namespaces, as
+// reported by Container Insights, and managed node groups whose CPU stayed near zero between
+// start and end. Clusters without Container Insights are only checked by node group.
+func ListUnusedEKSResources(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := eks.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	unusedResources := []models.UnusedResource{}
+
+	paginator := eks.NewListClustersPaginator(client, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list EKS clusters: %v", err)
+			return nil, fmt.Errorf("failed to list EKS clusters: %v", err)
+		}
+
+		for _, clusterName := range page.Clusters {
+			hasWorkloads, err := eksClusterHasWorkloads(ctx, cwClient, clusterName, start, end)
+			if err != nil {
+				log.Printf("Failed to check workloads in EKS cluster %s: %v", clusterName, err)
+			} else if !hasWorkloads {
+				unusedResources = append(unusedResources, models.UnusedResource{
+					ResourceType:         "eks:cluster",
+					ResourceID:           clusterName,
+					Reason:               fmt.Sprintf("No pods outside system namespaces for %d days", int(end.Sub(start).Hours()/24)),
+					EstimatedMonthlyCost: eksClusterHourlyPrice * hoursPerMonth,
+				})
+				log.Printf("Found unused EKS cluster: %s", clusterName)
+			}
+
+			nodegroups, err := listIdleEKSNodegroups(ctx, cfg, client, cwClient, region, clusterName, start, end)
+			if err != nil {
+				log.Printf("Failed to check node groups in EKS cluster %s: %v", clusterName, err)
+				continue
+			}
+			unusedResources = append(unusedResources, nodegroups...)
+		}
+	}
+
+	return unusedResources, nil
+}
+
+// eksClusterHasWorkloads reports whether Container Insights saw running pods in any non-system
+// namespace. Without Container Insights metrics it returns an error, as the answer is unknown.
+func eksClusterHasWorkloads(ctx context.Context, client *cloudwatch.Client, clusterName string, start, end time.Time) (bool, error) {
+	metrics := []cwtypes.Metric{}
+	paginator := cloudwatch.NewListMetricsPaginator(client, &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String("ContainerInsights"),
+		MetricName: aws.String("namespace_number_of_running_pods"),
+		Dimensions: []cwtypes.DimensionFilter{{Name: aws.String("ClusterName"), Value: aws.String(clusterName)}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, err
+		}
+		metrics = append(metrics, page.Metrics...)
+	}
+	if len(metrics) == 0 {
+		return false, fmt.Errorf("no Container Insights metrics for cluster %s", clusterName)
+	}
+
+	queries := []cwtypes.MetricDataQuery{}
+	for _, metric := range metrics {
+		namespace := ""
+		for _, dimension := range metric.Dimensions {
+			if aws.ToString(dimension.Name) == "Namespace" {
+				namespace = aws.ToString(dimension.Value)
+			}
+		}
+		if namespace == "" || eksSystemNamespaces[namespace] {
+			continue
+		}
+		queries = append(queries, metricQuery(fmt.Sprintf("pods%d", len(queries)), "ContainerInsights", "namespace_number_of_running_pods", "Maximum", metric.Dimensions, 86400, true))
+	}
+	if len(queries) == 0 {
+		return false, nil
+	}
+
+	values, err := getMetricData(ctx, client, queries, start, end)
+	if err != nil {
+		return false, err
+	}
+	for _, series := range values {
+		if maxValue(series) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// listIdleEKSNodegroups finds managed node groups whose Auto Scaling groups never averaged more
+// than eksIdleNodeCPU in any hour. Costs use on-demand prices, an upper bound for Spot node groups.
+func listIdleEKSNodegroups(ctx context.Context, cfg *config.Config, client *eks.Client, cwClient *cloudwatch.Client, region, clusterName string, start, end time.Time) ([]models.UnusedResource, error) {
+	idleNodegroups := []models.UnusedResource{}
+
+	paginator := eks.NewListNodegroupsPaginator(client, &eks.ListNodegroupsInput{ClusterName: aws.String(clusterName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, nodegroupName := range page.Nodegroups {
+			result, err := client.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String(clusterName),
+				NodegroupName: aws.String(nodegroupName),
+			})
+			if err != nil {
+				log.Printf("Failed to describe EKS node group %s/%s: %v", clusterName, nodegroupName, err)
+				continue
+			}
+			nodegroup := result.Nodegroup
+			if nodegroup.Resources == nil || nodegroup.ScalingConfig == nil || aws.ToInt32(nodegroup.ScalingConfig.DesiredSize) == 0 {
+				continue
+			}
+
+			queries := []cwtypes.MetricDataQuery{}
+			for _, group := range nodegroup.Resources.AutoScalingGroups {
+				dimensions := []cwtypes.Dimension{{Name: aws.String("AutoScalingGroupName"), Value: group.Name}}
+				queries = append(queries, metricQuery(fmt.Sprintf("cpu%d", len(queries)), "AWS/EC2", "CPUUtilization", "Average", dimensions, 3600, true))
+			}
+			if len(queries) == 0 {
+				continue
+			}
+			values, err := getMetricData(ctx, cwClient, queries, start, end)
+			if err != nil {
+				log.Printf("Failed to get CPU metrics for EKS node group %s/%s: %v", clusterName, nodegroupName, err)
+				continue
+			}
+			peakCPU, dataPoints := 0.0, 0
+			for _, series := range values {
+				peakCPU = max(peakCPU, maxValue(series))
+				dataPoints += len(series)
+			}
+			if dataPoints == 0 || peakCPU >= eksIdleNodeCPU {
+				continue
+			}
+
+			nodes := aws.ToInt32(nodegroup.ScalingConfig.DesiredSize)
+			monthlyCost := 0.0
+			if len(nodegroup.InstanceTypes) > 0 {
+				nodePrice, err := getEC2OnDemandPrice(ctx, cfg, region, nodegroup.InstanceTypes[0], "Linux/UNIX")
+				if err != nil {
+					log.Printf("No on-demand price for %s: %v", nodegroup.InstanceTypes[0], err)
+				}
+				monthlyCost = nodePrice * float64(nodes) * hoursPerMonth
+			}
+
+			idleNodegroups = append(idleNodegroups, models.UnusedResource{
+				ResourceType:         "eks:nodegroup",
+				ResourceID:           clusterName + "/" + nodegroupName,
+				Reason:               fmt.Sprintf("%d nodes with hourly CPU never above %.1f%%", nodes, peakCPU),
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found idle EKS node group: %s/%s", clusterName, nodegroupName)
+		}
+	}
+
+	return idleNodegroups, nil
+}
@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// elastiCachePricingEngines maps ElastiCache engines to Price List cacheEngine values.
+var elastiCachePricingEngines = map[string]string{
+	"redis":     "Redis",
+	"valkey":    "Valkey",
+	"memcached": "Memcached",
+}
+
+// ListUnusedElastiCacheClusters identifies available ElastiCache clusters that served no get or
+// set commands between start and end.
+func ListUnusedElastiCacheClusters(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := elasticache.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	unusedClusters := []models.UnusedResource{}
+
+	paginator := elasticache.NewDescribeCacheClustersPaginator(client, &elasticache.DescribeCacheClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe ElastiCache clusters: %v", err)
+			return nil, fmt.Errorf("failed to describe ElastiCache clusters: %v", err)
+		}
+
+		for _, cluster := range page.CacheClusters {
+			clusterID := aws.ToString(cluster.CacheClusterId)
+			engine := aws.ToString(cluster.Engine)
+			if aws.ToString(cluster.CacheClusterStatus) != "available" {
+				continue
+			}
+
+			// Memcached and Redis-compatible engines report commands under different names
+			getMetric, setMetric := "GetTypeCmds", "SetTypeCmds"
+			if engine == "memcached" {
+				getMetric, setMetric = "CmdGet", "CmdSet"
+			}
+			dimensions := []cwtypes.Dimension{{Name: aws.String("CacheClusterId"), Value: aws.String(clusterID)}}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("connections", "AWS/ElastiCache", "CurrConnections", "Maximum", dimensions, 86400, true),
+				metricQuery("gets", "AWS/ElastiCache", getMetric, "Sum", dimensions, 86400, true),
+				metricQuery("sets", "AWS/ElastiCache", setMetric, "Sum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for ElastiCache cluster %s: %v", clusterID, err)
+				continue
+			}
+			if sumValues(values["gets"])+sumValues(values["sets"]) > 0 {
+				continue
+			}
+
+			nodeType := aws.ToString(cluster.CacheNodeType)
+			nodes := aws.ToInt32(cluster.NumCacheNodes)
+			nodePrice, err := getNodeOnDemandPrice(ctx, cfg, "AmazonElastiCache", region, nodeType, map[string]string{
+				"cacheEngine": elastiCachePricingEngines[engine],
+			})
+			if err != nil {
+				log.Printf("No on-demand price for ElastiCache node type %s: %v", nodeType, err)
+			}
+
+			unusedClusters = append(unusedClusters, models.UnusedResource{
+				ResourceType: "elasticache:cluster",
+				ResourceID:   clusterID,
+				Reason: fmt.Sprintf("No get or set commands for %d days (peak %d connections, %d x %s)",
+					int(end.Sub(start).Hours()/24), int(maxValue(values["connections"])), nodes, nodeType),
+				EstimatedMonthlyCost: nodePrice * float64(nodes) * hoursPerMonth,
+			})
+			log.Printf("Found unused ElastiCache cluster: %s", clusterID)
+		}
+	}
+
+	return unusedClusters, nil
+}
@@ -2,60 +2,205 @@ package aws
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
-	// "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 	"github.com/deepanshumishra/devcost-api/internal/config"
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
 
-// ListUnusedLoadBalancers identifies load balancers with no registered targets.
-func ListUnusedLoadBalancers(ctx context.Context, cfg *config.Config) ([]models.UnusedResource, error) {
+// Load balancers with targets but below these totals over the window are reported as idle.
+const (
+	lbIdleRequests = 100               // HTTP requests
+	lbIdleBytes    = 100 * 1024 * 1024 // 100 MiB processed
+	lbIdleFlows    = 1                 // peak concurrent flows
+)
+
+// ListUnusedLoadBalancers identifies Application, Network, Gateway and Classic load balancers
+// with no registered targets or negligible traffic between start and end, and target groups
+// attached to no load balancer.
+func ListUnusedLoadBalancers(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
 	client := elasticloadbalancingv2.NewFromConfig(cfg.AWSConfig)
-	var unusedLBs []models.UnusedResource
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	unusedLBs := []models.UnusedResource{}
 
-	// List load balancers
-	lbInput := &elasticloadbalancingv2.DescribeLoadBalancersInput{}
-	lbResult, err := client.DescribeLoadBalancers(ctx, lbInput)
-	if err != nil {
-		return nil, err
+	// Map target groups to their load balancers, reporting unattached ones
+	targetGroups := make(map[string][]types.TargetGroup)
+	tgPaginator := elasticloadbalancingv2.NewDescribeTargetGroupsPaginator(client, &elasticloadbalancingv2.DescribeTargetGroupsInput{})
+	for tgPaginator.HasMorePages() {
+		page, err := tgPaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe target groups: %v", err)
+			return nil, fmt.Errorf("failed to describe target groups: %v", err)
+		}
+		for _, tg := range page.TargetGroups {
+			if len(tg.LoadBalancerArns) == 0 {
+				unusedLBs = append(unusedLBs, models.UnusedResource{
+					ResourceType: "elasticloadbalancing:targetgroup",
+					ResourceID:   aws.ToString(tg.TargetGroupArn),
+					Reason:       "Not attached to any load balancer",
+				})
+				continue
+			}
+			for _, lbArn := range tg.LoadBalancerArns {
+				targetGroups[lbArn] = append(targetGroups[lbArn], tg)
+			}
+		}
 	}
 
-	for _, lb := range lbResult.LoadBalancers {
-		// Get target groups for the load balancer
-		tgInput := &elasticloadbalancingv2.DescribeTargetGroupsInput{
-			LoadBalancerArn: lb.LoadBalancerArn,
-		}
-		tgResult, err := client.DescribeTargetGroups(ctx, tgInput)
+	lbPaginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(client, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+	for lbPaginator.HasMorePages() {
+		page, err := lbPaginator.NextPage(ctx)
 		if err != nil {
-			return nil, err
+			log.Printf("Failed to describe load balancers: %v", err)
+			return nil, fmt.Errorf("failed to describe load balancers: %v", err)
 		}
 
-		hasTargets := false
-		for _, tg := range tgResult.TargetGroups {
-			// Check target health
-			healthInput := &elasticloadbalancingv2.DescribeTargetHealthInput{
-				TargetGroupArn: tg.TargetGroupArn,
-			}
-			healthResult, err := client.DescribeTargetHealth(ctx, healthInput)
-			if err != nil {
-				return nil, err
+		for _, lb := range page.LoadBalancers {
+			lbArn := aws.ToString(lb.LoadBalancerArn)
+
+			hasTargets := false
+			for _, tg := range targetGroups[lbArn] {
+				// Check target health
+				healthResult, err := client.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+					TargetGroupArn: tg.TargetGroupArn,
+				})
+				if err != nil {
+					// Without target health, let the load balancer's traffic decide
+					log.Printf("Failed to describe target health for %s, assuming it has targets: %v", aws.ToString(tg.TargetGroupArn), err)
+					hasTargets = true
+					break
+				}
+				if len(healthResult.TargetHealthDescriptions) > 0 {
+					hasTargets = true
+					break
+				}
 			}
-			if len(healthResult.TargetHealthDescriptions) > 0 {
-				hasTargets = true
-				break
+
+			reason := "No registered targets"
+			if hasTargets {
+				reason, err = idleLoadBalancerReason(ctx, cwClient, lb, start, end)
+				if err != nil {
+					log.Printf("Failed to get metrics for load balancer %s: %v", lbArn, err)
+					continue
+				}
+				if reason == "" {
+					continue
+				}
 			}
+
+			unusedLBs = append(unusedLBs, models.UnusedResource{
+				ResourceType:         "elasticloadbalancing:loadbalancer",
+				ResourceID:           lbArn,
+				Reason:               reason,
+				EstimatedMonthlyCost: loadBalancerHourlyPrices[string(lb.Type)] * hoursPerMonth,
+			})
+			log.Printf("Found unused load balancer: %s (%s)", lbArn, reason)
+		}
+	}
+
+	classicLBs, err := listUnusedClassicLoadBalancers(ctx, cfg, cwClient, start, end)
+	if err != nil {
+		if len(unusedLBs) == 0 {
+			return nil, err
 		}
+		log.Printf("Keeping %d load balancer findings after Classic Load Balancers failed: %v", len(unusedLBs), err)
+	}
+	unusedLBs = append(unusedLBs, classicLBs...)
+
+	return unusedLBs, nil
+}
+
+// idleLoadBalancerReason checks an Application, Network or Gateway load balancer's traffic and
+// returns why it is idle, or "" when it carried traffic.
+func idleLoadBalancerReason(ctx context.Context, client *cloudwatch.Client, lb types.LoadBalancer, start, end time.Time) (string, error) {
+	// Metrics are keyed by the ARN suffix, e.g. "app/my-lb/50dc6c495c0c9188"
+	lbArn := aws.ToString(lb.LoadBalancerArn)
+	dimensions := []cwtypes.Dimension{{Name: aws.String("LoadBalancer"), Value: aws.String(lbArn[strings.Index(lbArn, "loadbalancer/")+len("loadbalancer/"):])}}
+	days := int(end.Sub(start).Hours() / 24)
+
+	if lb.Type == types.LoadBalancerTypeEnumApplication {
+		values, err := getMetricData(ctx, client, []cwtypes.MetricDataQuery{
+			metricQuery("requests", "AWS/ApplicationELB", "RequestCount", "Sum", dimensions, 86400, true),
+		}, start, end)
+		if err != nil {
+			return "", err
+		}
+		if requests := sumValues(values["requests"]); requests < lbIdleRequests {
+			return fmt.Sprintf("%d requests in %d days", int(requests), days), nil
+		}
+		return "", nil
+	}
+
+	namespace := "AWS/NetworkELB"
+	if lb.Type == types.LoadBalancerTypeEnumGateway {
+		namespace = "AWS/GatewayELB"
+	}
+	values, err := getMetricData(ctx, client, []cwtypes.MetricDataQuery{
+		metricQuery("bytes", namespace, "ProcessedBytes", "Sum", dimensions, 86400, true),
+		metricQuery("flows", namespace, "ActiveFlowCount", "Maximum", dimensions, 86400, true),
+	}, start, end)
+	if err != nil {
+		return "", err
+	}
+	if bytes := sumValues(values["bytes"]); bytes < lbIdleBytes && maxValue(values["flows"]) <= lbIdleFlows {
+		return fmt.Sprintf("%.1f MiB processed in %d days", bytes/1024/1024, days), nil
+	}
+	return "", nil
+}
+
+// listUnusedClassicLoadBalancers identifies Classic load balancers with no registered instances
+// or negligible requests and processed bytes between start and end.
+func listUnusedClassicLoadBalancers(ctx context.Context, cfg *config.Config, cwClient *cloudwatch.Client, start, end time.Time) ([]models.UnusedResource, error) {
+	client := elasticloadbalancing.NewFromConfig(cfg.AWSConfig)
+	unusedLBs := []models.UnusedResource{}
+	days := int(end.Sub(start).Hours() / 24)
+
+	paginator := elasticloadbalancing.NewDescribeLoadBalancersPaginator(client, &elasticloadbalancing.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Classic load balancers: %v", err)
+			return nil, fmt.Errorf("failed to describe Classic load balancers: %v", err)
+		}
+
+		for _, lb := range page.LoadBalancerDescriptions {
+			lbName := aws.ToString(lb.LoadBalancerName)
+			reason := "No registered instances"
+			if len(lb.Instances) > 0 {
+				dimensions := []cwtypes.Dimension{{Name: aws.String("LoadBalancerName"), Value: aws.String(lbName)}}
+				values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+					metricQuery("requests", "AWS/ELB", "RequestCount", "Sum", dimensions, 86400, true),
+					metricQuery("bytes", "AWS/ELB", "EstimatedProcessedBytes", "Sum", dimensions, 86400, true),
+				}, start, end)
+				if err != nil {
+					log.Printf("Failed to get metrics for Classic load balancer %s: %v", lbName, err)
+					continue
+				}
+				requests, bytes := sumValues(values["requests"]), sumValues(values["bytes"])
+				if requests >= lbIdleRequests || bytes >= lbIdleBytes {
+					continue
+				}
+				reason = fmt.Sprintf("%d requests and %.1f MiB processed in %d days", int(requests), bytes/1024/1024, days)
+			}
 
-		if !hasTargets {
 			unusedLBs = append(unusedLBs, models.UnusedResource{
-				ResourceType: "elasticloadbalancing:loadbalancer",
-				ResourceID:   aws.ToString(lb.LoadBalancerArn),
-				Reason:       "No registered targets",
+				ResourceType:         "elasticloadbalancing:loadbalancer/classic",
+				ResourceID:           lbName,
+				Reason:               reason,
+				EstimatedMonthlyCost: classicLoadBalancerHourlyPrice * hoursPerMonth,
 			})
+			log.Printf("Found unused Classic load balancer: %s (%s)", lbName, reason)
 		}
 	}
 
 	return unusedLBs, nil
-}
\ No newline at end of file
+}
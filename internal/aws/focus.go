@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/focus"
+)
+
+// focusImportPrefix prefixes the store partitions of imported FOCUS files.
+const focusImportPrefix = "focus://import/"
+
+// ErrFOCUSImportExists is returned when a different FOCUS file with the same name was imported.
+var ErrFOCUSImportExists = fmt.Errorf("a different FOCUS file with this name is already imported, set replace=true to replace it")
+
+// ExportFOCUS returns costs for the date range as FOCUS rows. With source "cur" it exports
+// ingested line items, including resource IDs and tags; otherwise it exports daily Cost
+// Explorer costs by service and record type.
+func ExportFOCUS(ctx context.Context, cfg *config.Config, source string, start, end time.Time) ([]focus.Row, error) {
+	rows := []focus.Row{}
+
+	if source == "cur" {
+		if cfg.CURStore == nil || cfg.CURStore.Len() == 0 {
+			return nil, fmt.Errorf("no CUR data has been ingested")
+		}
+		cfg.CURStore.Scan(start, end, func(item cur.LineItem) {
+			rows = append(rows, focus.FromLineItem(item))
+		})
+		log.Printf("Exported %d FOCUS rows from CUR data from %s to %s", len(rows), start.Format("2006-01-02"), end.Format("2006-01-02"))
+		return rows, nil
+	}
+
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &types.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+		Granularity: types.GranularityDaily,
+		Metrics:     []string{"UnblendedCost", "AmortizedCost"},
+		GroupBy: []types.GroupDefinition{
+			{
+				Type: types.GroupDefinitionTypeDimension,
+				Key:  aws.String("SERVICE"),
+			},
+			{
+				Type: types.GroupDefinitionTypeDimension,
+				Key:  aws.String("RECORD_TYPE"),
+			},
+		},
+	}
+
+	for {
+		result, err := client.GetCostAndUsage(ctx, input)
+		if err != nil {
+			log.Printf("Failed to get cost and usage for FOCUS export: %v", err)
+			return nil, fmt.Errorf("failed to fetch costs for export: %v", err)
+		}
+
+		for _, byTime := range result.ResultsByTime {
+			chargeStart, _ := time.Parse("2006-01-02", aws.ToString(byTime.TimePeriod.Start))
+			chargeEnd, _ := time.Parse("2006-01-02", aws.ToString(byTime.TimePeriod.End))
+			periodStart, periodEnd := focus.BillingPeriod(chargeStart)
+			for _, group := range byTime.Groups {
+				billed, err := strconv.ParseFloat(aws.ToString(group.Metrics["UnblendedCost"].Amount), 64)
+				if err != nil {
+					log.Printf("Failed to parse cost for %v on %s: %v", group.Keys, aws.ToString(byTime.TimePeriod.Start), err)
+					continue
+				}
+				effective, err := strconv.ParseFloat(aws.ToString(group.Metrics["AmortizedCost"].Amount), 64)
+				if err != nil {
+					effective = billed
+				}
+				rows = append(rows, focus.Row{
+					BillingCurrency:    aws.ToString(group.Metrics["UnblendedCost"].Unit),
+					BillingPeriodStart: periodStart,
+					BillingPeriodEnd:   periodEnd,
+					ChargePeriodStart:  chargeStart,
+					ChargePeriodEnd:    chargeEnd,
+					ChargeCategory:     focus.ChargeCategory(group.Keys[1]),
+					BilledCost:         billed,
+					EffectiveCost:      effective,
+					ProviderName:       "AWS",
+					PublisherName:      "AWS",
+					InvoiceIssuerName:  "AWS",
+					ServiceName:        group.Keys[0],
+				})
+			}
+		}
+
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	log.Printf("Exported %d FOCUS rows from Cost Explorer from %s to %s", len(rows), start.Format("2006-01-02"), end.Format("2006-01-02"))
+	return rows, nil
+}
+
+// ImportFOCUS stores a FOCUS CSV or Parquet file, e.g. an Azure or GCP cost export, alongside
+// ingested CUR data so its costs appear in CUR-backed cost views. Imports are keyed by file
+// name: re-importing the same content is a no-op, and a different file with the name of an
+// earlier import, e.g. another cloud's export.csv, returns ErrFOCUSImportExists unless
+// replace is set.
+func ImportFOCUS(cfg *config.Config, filename string, data []byte, replace bool) (int, error) {
+	items, err := cur.ReadFile(filename, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		log.Printf("Failed to parse FOCUS file %s: %v", filename, err)
+		return 0, fmt.Errorf("failed to parse FOCUS file: %v", err)
+	}
+
+	checksum := sha256.Sum256(data)
+	version := hex.EncodeToString(checksum[:])
+	source := focusImportPrefix + filepath.Base(filename)
+	if cfg.CURStore.HasVersion(source, version) {
+		log.Printf("FOCUS file %s is already imported", filename)
+		return len(items), nil
+	}
+	if cfg.CURStore.Has(source) && !replace {
+		return 0, ErrFOCUSImportExists
+	}
+	cfg.CURStore.Replace(source, version, items)
+	if err := cfg.CURStore.Save(); err != nil {
+		return 0, fmt.Errorf("failed to store FOCUS data: %v", err)
+	}
+
+	log.Printf("Imported %d FOCUS rows from %s", len(items), filename)
+	return len(items), nil
+}
@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/focus"
+)
+
+func TestImportFOCUSKeepsFilesWithTheSameName(t *testing.T) {
+	chargeStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	export := func(provider string, cost float64) []byte {
+		var buf bytes.Buffer
+		rows := []focus.Row{{
+			BillingCurrency:   "USD",
+			ChargePeriodStart: chargeStart,
+			ChargePeriodEnd:   chargeStart.AddDate(0, 0, 1),
+			ChargeCategory:    "Usage",
+			BilledCost:        cost,
+			EffectiveCost:     cost,
+			ProviderName:      provider,
+			ServiceName:       "Compute",
+		}}
+		if err := focus.WriteCSV(&buf, rows); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return buf.Bytes()
+	}
+	store, _ := cur.NewStore("")
+	cfg := &config.Config{CURStore: store}
+	total := func() float64 {
+		sum := 0.0
+		store.Scan(chargeStart, chargeStart.AddDate(0, 0, 1), func(item cur.LineItem) { sum += item.UnblendedCost })
+		return sum
+	}
+
+	azure, gcp := export("Microsoft", 3), export("Google Cloud", 4)
+	if _, err := ImportFOCUS(cfg, "azure/export.csv", azure, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Importing the same file again changes nothing
+	if _, err := ImportFOCUS(cfg, "export.csv", azure, false); err != nil {
+		t.Errorf("expected re-importing the same file to succeed, got %v", err)
+	}
+	if _, err := ImportFOCUS(cfg, "gcp/export.csv", gcp, false); !errors.Is(err, ErrFOCUSImportExists) {
+		t.Errorf("expected ErrFOCUSImportExists for a different file with the same name, got %v", err)
+	}
+	if got := total(); got != 3 {
+		t.Errorf("expected the first import to be kept at 3, got %.2f", got)
+	}
+	if _, err := ImportFOCUS(cfg, "gcp/export.csv", gcp, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := total(); got != 4 {
+		t.Errorf("expected the replacement at 4, got %.2f", got)
+	}
+}
@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// kmsCryptographicEvents are the CloudTrail events that count as using a KMS key.
+var kmsCryptographicEvents = map[string]bool{
+	"Encrypt":                             true,
+	"Decrypt":                             true,
+	"ReEncrypt":                           true,
+	"GenerateDataKey":                     true,
+	"GenerateDataKeyWithoutPlaintext":     true,
+	"GenerateDataKeyPair":                 true,
+	"GenerateDataKeyPairWithoutPlaintext": true,
+	"Sign":                                true,
+	"Verify":                              true,
+	"GenerateMac":                         true,
+	"VerifyMac":                           true,
+	"DeriveSharedSecret":                  true,
+}
+
+// ListUnusedKMSKeys identifies customer managed KMS keys with no cryptographic operations in
+// CloudTrail event history for the specified number of days (at most 90).
+func ListUnusedKMSKeys(ctx context.Context, cfg *config.Config, unusedForDays int) ([]models.UnusedResource, error) {
+	client := kms.NewFromConfig(cfg.AWSConfig)
+	trailClient := cloudtrail.NewFromConfig(cfg.AWSConfig)
+	unusedKeys := []models.UnusedResource{}
+	since := time.Now().AddDate(0, 0, -unusedForDays)
+
+	paginator := kms.NewListKeysPaginator(client, &kms.ListKeysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list KMS keys: %v", err)
+			return nil, fmt.Errorf("failed to list KMS keys: %v", err)
+		}
+
+		for _, key := range page.Keys {
+			keyArn := aws.ToString(key.KeyArn)
+			described, err := client.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: key.KeyId})
+			if err != nil {
+				log.Printf("Failed to describe KMS key %s: %v", keyArn, err)
+				continue
+			}
+			metadata := described.KeyMetadata
+			// AWS managed keys are free, and keys pending deletion are no longer billed
+			if metadata.KeyManager != types.KeyManagerTypeCustomer ||
+				metadata.KeyState == types.KeyStatePendingDeletion || metadata.KeyState == types.KeyStatePendingReplicaDeletion {
+				continue
+			}
+			if metadata.CreationDate != nil && metadata.CreationDate.After(since) {
+				continue
+			}
+
+			used, err := hasCloudTrailEvent(ctx, trailClient, keyArn, kmsCryptographicEvents, since)
+			if err != nil {
+				log.Printf("Failed to look up CloudTrail events for KMS key %s: %v", keyArn, err)
+				continue
+			}
+			if used {
+				continue
+			}
+
+			unusedKeys = append(unusedKeys, models.UnusedResource{
+				ResourceType:         "kms:key",
+				ResourceID:           keyArn,
+				Reason:               "No cryptographic operations in " + strconv.Itoa(min(unusedForDays, cloudTrailLookbackDays)) + " days (" + string(metadata.KeyState) + ")",
+				EstimatedMonthlyCost: kmsKeyMonthlyPrice,
+			})
+			log.Printf("Found unused KMS key: %s", keyArn)
+		}
+	}
+
+	return unusedKeys, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/deepanshumishra/devcost-api/internal/config"
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
@@ -23,15 +24,14 @@ func ListUnusedLambdaResources(cfg *config.Config, start, end time.Time, unusedF
 	unusedResources := []models.UnusedResource{}
 
 	// List Lambda functions
-	input := &lambda.ListFunctionsInput{}
-	result, err := client.ListFunctions(context.TODO(), input)
+	functions, err := listLambdaFunctions(context.TODO(), client)
 	if err != nil {
 		log.Printf("Failed to list Lambda functions: %v", err)
 		return unusedResources, err
 	}
 
 	// Check for unused Lambda functions
-	for _, function := range result.Functions {
+	for _, function := range functions {
 		isUnused, err := isLambdaFunctionUnused(cfg, aws.ToString(function.FunctionArn), start, end)
 		if err != nil {
 			log.Printf("Failed to check usage for Lambda %s: %v", aws.ToString(function.FunctionArn), err)
@@ -50,6 +50,20 @@ func ListUnusedLambdaResources(cfg *config.Config, start, end time.Time, unusedF
 	return unusedResources, nil
 }
 
+// listLambdaFunctions lists all Lambda functions in the region.
+func listLambdaFunctions(ctx context.Context, client *lambda.Client) ([]lambdatypes.FunctionConfiguration, error) {
+	functions := []lambdatypes.FunctionConfiguration{}
+	paginator := lambda.NewListFunctionsPaginator(client, &lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, page.Functions...)
+	}
+	return functions, nil
+}
+
 // isLambdaFunctionUnused checks if a Lambda function has no invocations.
 func isLambdaFunctionUnused(cfg *config.Config, functionArn string, start, end time.Time) (bool, error) {
 	client := cloudwatch.NewFromConfig(cfg.AWSConfig)
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/deepanshumishra/devcost-api/internal/rightsizing"
+)
+
+// List prices used for cost estimates, in USD at us-east-1 on-demand rates. Other regions are
+// typically within a few percent, so estimates are indicative rather than billed amounts.
+const (
+	// hoursPerMonth converts hourly prices to monthly estimates.
+	hoursPerMonth = rightsizing.HoursPerMonth
+
+	natGatewayHourlyPrice = 0.045 // per NAT Gateway hour
+	natGatewayPerGBPrice  = 0.045 // per GB processed
+
+	ebsSnapshotPerGBMonthPrice = 0.05 // standard tier snapshot storage
+
+	ebsGP2PerGBMonthPrice        = 0.10
+	ebsGP3PerGBMonthPrice        = 0.08
+	ebsGP3PerIOPSMonthPrice      = 0.005 // above the 3,000 included IOPS
+	ebsGP3PerMBpsMonthPrice      = 0.04  // above the 125 MB/s included throughput
+	ebsIO1PerGBMonthPrice        = 0.125
+	ebsIO1PerIOPSMonthPrice      = 0.065
+	ebsIO2PerGBMonthPrice        = 0.125
+	ebsIO2PerIOPSMonthPrice      = 0.065  // up to 32,000 IOPS
+	ebsIO2PerIOPSMonthPriceTier2 = 0.0455 // 32,001 to 64,000 IOPS
+	ebsIO2PerIOPSMonthPriceTier3 = 0.032  // above 64,000 IOPS
+
+	rdsStoragePerGBMonthPrice               = 0.115 // gp2, single-AZ
+	rdsBackupPerGBMonthPrice                = 0.095 // backup storage beyond the free allocation
+	auroraBackupPerGBMonthPrice             = 0.021
+	auroraStoragePerGBMonthPrice            = 0.10
+	auroraIOOptimizedStoragePerGBMonthPrice = 0.225
+	auroraServerlessV2ACUHourlyPrice        = 0.12
+
+	classicLoadBalancerHourlyPrice = 0.025
+
+	publicIPv4HourlyPrice               = 0.005 // per in-use or idle public IPv4 address
+	vpcInterfaceEndpointHourlyPrice     = 0.01  // per endpoint per Availability Zone
+	transitGatewayAttachmentHourlyPrice = 0.05
+	clientVPNAssociationHourlyPrice     = 0.10 // per associated subnet
+	siteToSiteVPNHourlyPrice            = 0.05 // per connection
+
+	eksClusterHourlyPrice = 0.10 // control plane, standard support
+	ecrPerGBMonthPrice    = 0.10
+
+	logsStoragePerGBMonthPrice = 0.03 // compressed log storage
+
+	kmsKeyMonthlyPrice                  = 1.00 // per customer managed key, before rotations
+	route53HostedZoneMonthlyPrice       = 0.50 // first 25 hosted zones
+	privateCAGeneralPurposeMonthlyPrice = 400.0
+	privateCAShortLivedMonthlyPrice     = 50.0
+
+	s3StandardPerGBMonthPrice                    = 0.023  // first 50 TB
+	s3InfrequentAccessPerGBMonthPrice            = 0.0125 // Standard-IA and the Intelligent-Tiering infrequent access tier
+	s3IntelligentTieringMonitoringPer1000Objects = 0.0025
+
+	dynamoDBReadRequestPrice             = 0.125 / 1e6 // on-demand, per read request unit
+	dynamoDBWriteRequestPrice            = 0.625 / 1e6 // on-demand, per write request unit
+	dynamoDBReadCapacityUnitHourlyPrice  = 0.00013
+	dynamoDBWriteCapacityUnitHourlyPrice = 0.00065
+	dynamoDBStandardPerGBMonthPrice      = 0.25
+	dynamoDBStandardIAPerGBMonthPrice    = 0.10
+	dynamoDBStandardIAThroughputPremium  = 0.25 // Standard-IA throughput costs 25% more
+
+	openSearchServerlessOCUHourlyPrice = 0.24
+	openSearchServerlessMinOCUs        = 2 // one indexing and one search OCU with redundancy disabled
+)
+
+// loadBalancerHourlyPrices are the fixed hourly prices by load balancer type, excluding capacity units.
+var loadBalancerHourlyPrices = map[string]float64{
+	"application": 0.0225,
+	"network":     0.0225,
+	"gateway":     0.0125,
+}
+
+// bytesPerGB converts byte counts from CloudWatch to the GB units AWS bills in.
+const bytesPerGB = 1024 * 1024 * 1024
+
+// tokenPrice is an on-demand price per 1,000 input and output tokens.
+type tokenPrice struct {
+	input, output float64
+}
+
+// bedrockTokenPrices are on-demand token prices by Bedrock model ID prefix.
+var bedrockTokenPrices = map[string]tokenPrice{
+	"anthropic.claude-opus-4-5":     {0.005, 0.025},
+	"anthropic.claude-opus-4":       {0.015, 0.075},
+	"anthropic.claude-haiku-4-5":    {0.001, 0.005},
+	"anthropic.claude-sonnet-4":     {0.003, 0.015},
+	"anthropic.claude-3-7-sonnet":   {0.003, 0.015},
+	"anthropic.claude-3-5-sonnet":   {0.003, 0.015},
+	"anthropic.claude-3-5-haiku":    {0.0008, 0.004},
+	"anthropic.claude-3-opus":       {0.015, 0.075},
+	"anthropic.claude-3-sonnet":     {0.003, 0.015},
+	"anthropic.claude-3-haiku":      {0.00025, 0.00125},
+	"amazon.nova-premier":           {0.0025, 0.0125},
+	"amazon.nova-pro":               {0.0008, 0.0032},
+	"amazon.nova-lite":              {0.00006, 0.00024},
+	"amazon.nova-micro":             {0.000035, 0.00014},
+	"amazon.titan-text-express":     {0.0002, 0.0006},
+	"amazon.titan-text-lite":        {0.00015, 0.0002},
+	"amazon.titan-embed-text":       {0.00002, 0},
+	"cohere.embed":                  {0.0001, 0},
+	"meta.llama3-1-8b-instruct":     {0.00022, 0.00022},
+	"meta.llama3-1-70b-instruct":    {0.00072, 0.00072},
+	"meta.llama3-3-70b-instruct":    {0.00072, 0.00072},
+	"mistral.mistral-large-2407":    {0.002, 0.006},
+	"mistral.mistral-small-2402":    {0.001, 0.003},
+	"mistral.mixtral-8x7b-instruct": {0.00045, 0.0007},
+}
+
+// bedrockModelUnitPrices are hourly Provisioned Throughput prices per model unit by foundation
+// model ID prefix and commitment term; an empty term is no commitment.
+var bedrockModelUnitPrices = map[string]map[bedrocktypes.CommitmentDuration]float64{
+	"amazon.titan-text-express": {"": 20.50, bedrocktypes.CommitmentDurationOneMonth: 18.40, bedrocktypes.CommitmentDurationSixMonths: 14.80},
+	"amazon.titan-text-lite":    {"": 7.10, bedrocktypes.CommitmentDurationOneMonth: 6.40, bedrocktypes.CommitmentDurationSixMonths: 5.10},
+	"amazon.titan-embed-text":   {bedrocktypes.CommitmentDurationOneMonth: 6.40, bedrocktypes.CommitmentDurationSixMonths: 5.10},
+	"anthropic.claude-instant":  {"": 44.00, bedrocktypes.CommitmentDurationOneMonth: 39.60, bedrocktypes.CommitmentDurationSixMonths: 22.00},
+	"anthropic.claude-v2":       {"": 70.00, bedrocktypes.CommitmentDurationOneMonth: 63.00, bedrocktypes.CommitmentDurationSixMonths: 35.00},
+	"cohere.command-text":       {"": 49.50, bedrocktypes.CommitmentDurationOneMonth: 39.60, bedrocktypes.CommitmentDurationSixMonths: 23.77},
+}
@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// logsStaleMinBytes is the stored size above which a log group with no ingestion is reported.
+const logsStaleMinBytes = 1024 * 1024 * 1024
+
+// lambdaLogGroupPrefix is the log group name prefix Lambda writes function logs under.
+const lambdaLogGroupPrefix = "/aws/lambda/"
+
+// ListUnusedLogGroups identifies CloudWatch Logs log groups with no retention policy, over 1 GiB
+// stored but nothing ingested between start and end, or belonging to deleted Lambda functions.
+func ListUnusedLogGroups(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := cloudwatchlogs.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	unusedLogGroups := []models.UnusedResource{}
+
+	functions, err := listLambdaFunctions(ctx, lambda.NewFromConfig(cfg.AWSConfig))
+	if err != nil {
+		log.Printf("Failed to list Lambda functions: %v", err)
+		return nil, fmt.Errorf("failed to list Lambda functions: %v", err)
+	}
+	functionNames := make(map[string]bool)
+	for _, function := range functions {
+		functionNames[aws.ToString(function.FunctionName)] = true
+	}
+
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(client, &cloudwatchlogs.DescribeLogGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe log groups: %v", err)
+			return nil, fmt.Errorf("failed to describe log groups: %v", err)
+		}
+
+		for _, logGroup := range page.LogGroups {
+			logGroupName := aws.ToString(logGroup.LogGroupName)
+			storedBytes := aws.ToInt64(logGroup.StoredBytes)
+			storedGB := float64(storedBytes) / bytesPerGB
+			reasons := []string{}
+
+			if functionName, ok := strings.CutPrefix(logGroupName, lambdaLogGroupPrefix); ok && !functionNames[functionName] {
+				reasons = append(reasons, fmt.Sprintf("Lambda function %s no longer exists", functionName))
+			}
+			if logGroup.RetentionInDays == nil {
+				reasons = append(reasons, "No retention policy")
+			}
+			if storedBytes >= logsStaleMinBytes {
+				dimensions := []cwtypes.Dimension{{Name: aws.String("LogGroupName"), Value: aws.String(logGroupName)}}
+				values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+					metricQuery("incoming", "AWS/Logs", "IncomingBytes", "Sum", dimensions, 86400, true),
+				}, start, end)
+				if err != nil {
+					log.Printf("Failed to get metrics for log group %s: %v", logGroupName, err)
+				} else if sumValues(values["incoming"]) == 0 {
+					reasons = append(reasons, fmt.Sprintf("No ingestion for %d days", int(end.Sub(start).Hours()/24)))
+				}
+			}
+			if len(reasons) == 0 {
+				continue
+			}
+
+			unusedLogGroups = append(unusedLogGroups, models.UnusedResource{
+				ResourceType:         "logs:log-group",
+				ResourceID:           logGroupName,
+				Reason:               fmt.Sprintf("%s (%.2f GiB stored)", strings.Join(reasons, "; "), storedGB),
+				EstimatedMonthlyCost: storedGB * logsStoragePerGBMonthPrice,
+			})
+			log.Printf("Found unused log group: %s", logGroupName)
+		}
+	}
+
+	return unusedLogGroups, nil
+}
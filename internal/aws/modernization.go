@@ -0,0 +1,262 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/deepanshumishra/devcost-api/internal/rightsizing"
+)
+
+// gravitonRDSEngines lists the RDS engines that run on Graviton instance classes.
+var gravitonRDSEngines = map[string]bool{
+	"mysql":             true,
+	"postgres":          true,
+	"mariadb":           true,
+	"aurora-mysql":      true,
+	"aurora-postgresql": true,
+}
+
+// ListModernizationOptimizations finds resources on older or pricier configurations with a drop-in
+// replacement: gp2 and io1 EBS volumes, previous-generation EC2 and RDS instances, and x86
+// instances with a cheaper Graviton equivalent. Unlike unused resources, these are in use.
+func ListModernizationOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	optimizations := []models.Optimization{}
+	var errors []error
+
+	volumeOptimizations, err := listEBSModernizations(ctx, cfg)
+	if err != nil {
+		errors = append(errors, err)
+	} else {
+		optimizations = append(optimizations, volumeOptimizations...)
+	}
+
+	ec2Optimizations, err := listEC2Modernizations(ctx, cfg)
+	if err != nil {
+		errors = append(errors, err)
+	} else {
+		optimizations = append(optimizations, ec2Optimizations...)
+	}
+
+	rdsOptimizations, err := listRDSModernizations(ctx, cfg)
+	if err != nil {
+		errors = append(errors, err)
+	} else {
+		optimizations = append(optimizations, rdsOptimizations...)
+	}
+
+	if len(optimizations) == 0 && len(errors) > 0 {
+		return nil, fmt.Errorf("failed to list modernization optimizations: %v", errors[0])
+	}
+
+	log.Printf("Found %d modernization optimizations", len(optimizations))
+	return optimizations, nil
+}
+
+// listEBSModernizations compares gp2 volumes with gp3 at the same baseline performance, and io1
+// volumes with io2 or, when the IOPS fit, gp3.
+func listEBSModernizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	client := ec2.NewFromConfig(cfg.AWSConfig)
+	optimizations := []models.Optimization{}
+
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("volume-type"),
+				Values: []string{string(types.VolumeTypeGp2), string(types.VolumeTypeIo1)},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe EBS volumes: %v", err)
+			return nil, fmt.Errorf("failed to describe EBS volumes: %v", err)
+		}
+
+		for _, volume := range page.Volumes {
+			sizeGiB := float64(aws.ToInt32(volume.Size))
+			iops := float64(aws.ToInt32(volume.Iops))
+			optimization := models.Optimization{
+				Category:     "modernization",
+				ResourceType: "ebs:volume",
+				ResourceID:   aws.ToString(volume.VolumeId),
+			}
+
+			switch volume.VolumeType {
+			case types.VolumeTypeGp2:
+				// gp2 bursts to 250 MB/s above 170 GiB; keep that throughput on gp3
+				throughput := 128.0
+				if sizeGiB > 170 {
+					throughput = 250
+				}
+				current := sizeGiB * ebsGP2PerGBMonthPrice
+				target := gp3MonthlyCost(sizeGiB, iops, throughput)
+				optimization.CurrentConfiguration = fmt.Sprintf("gp2, %.0f GiB, %.0f IOPS", sizeGiB, iops)
+				optimization.RecommendedConfiguration = fmt.Sprintf("gp3, %.0f GiB, %.0f IOPS, %.0f MB/s", sizeGiB, math.Max(iops, 3000), math.Max(throughput, 125))
+				optimization.Reason = "gp3 is 20% cheaper per GiB with 3,000 IOPS included"
+				optimization.EstimatedMonthlySavings = current - target
+			case types.VolumeTypeIo1:
+				current := sizeGiB*ebsIO1PerGBMonthPrice + iops*ebsIO1PerIOPSMonthPrice
+				target := io2MonthlyCost(sizeGiB, iops)
+				optimization.CurrentConfiguration = fmt.Sprintf("io1, %.0f GiB, %.0f IOPS", sizeGiB, iops)
+				optimization.RecommendedConfiguration = fmt.Sprintf("io2, %.0f GiB, %.0f IOPS", sizeGiB, iops)
+				optimization.Reason = "io2 has higher durability and tiered IOPS pricing"
+				if iops <= 16000 {
+					if gp3 := gp3MonthlyCost(sizeGiB, iops, 125); gp3 < target {
+						target = gp3
+						optimization.RecommendedConfiguration = fmt.Sprintf("gp3, %.0f GiB, %.0f IOPS", sizeGiB, iops)
+						optimization.Reason = "gp3 supports the provisioned IOPS at a lower price"
+					}
+				}
+				optimization.EstimatedMonthlySavings = current - target
+			}
+
+			if optimization.EstimatedMonthlySavings > 0 {
+				optimizations = append(optimizations, optimization)
+			}
+		}
+	}
+
+	return optimizations, nil
+}
+
+// gp3MonthlyCost is the monthly list price of a gp3 volume provisioned with the given IOPS and
+// throughput (MB/s).
+func gp3MonthlyCost(sizeGiB, iops, throughput float64) float64 {
+	return sizeGiB*ebsGP3PerGBMonthPrice + math.Max(0, iops-3000)*ebsGP3PerIOPSMonthPrice + math.Max(0, throughput-125)*ebsGP3PerMBpsMonthPrice
+}
+
+// io2MonthlyCost is the monthly list price of an io2 volume with tiered IOPS pricing.
+func io2MonthlyCost(sizeGiB, iops float64) float64 {
+	cost := sizeGiB * ebsIO2PerGBMonthPrice
+	cost += math.Min(iops, 32000) * ebsIO2PerIOPSMonthPrice
+	cost += math.Max(0, math.Min(iops, 64000)-32000) * ebsIO2PerIOPSMonthPriceTier2
+	cost += math.Max(0, iops-64000) * ebsIO2PerIOPSMonthPriceTier3
+	return cost
+}
+
+// listEC2Modernizations finds running instances of previous-generation families and, for Linux
+// instances, x86 types with a cheaper Graviton equivalent.
+func listEC2Modernizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	client := ec2.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	optimizations := []models.Optimization{}
+
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"running"},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe EC2 instances: %v", err)
+			return nil, fmt.Errorf("failed to describe EC2 instances: %v", err)
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceType := string(instance.InstanceType)
+				platform := aws.ToString(instance.PlatformDetails)
+				price := func(instanceType string) (float64, error) {
+					return getEC2OnDemandPrice(ctx, cfg, region, instanceType, platform)
+				}
+
+				target, reason := "", ""
+				if successor, ok := rightsizing.SuccessorType(instanceType); ok {
+					target, reason = successor, "Previous-generation instance family"
+				} else if graviton, ok := rightsizing.GravitonType(instanceType); ok && platform == "Linux/UNIX" {
+					target, reason = graviton, "Graviton equivalent is cheaper for Linux workloads"
+				} else {
+					continue
+				}
+
+				if optimization, ok := instanceModernization("ec2:instance", aws.ToString(instance.InstanceId), instanceType, target, reason, price); ok {
+					optimizations = append(optimizations, optimization)
+				}
+			}
+		}
+	}
+
+	return optimizations, nil
+}
+
+// listRDSModernizations finds RDS instances of previous-generation classes and, for engines that
+// support it, x86 classes with a cheaper Graviton equivalent.
+func listRDSModernizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	client := rds.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	optimizations := []models.Optimization{}
+
+	paginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS instances: %v", err)
+			return nil, fmt.Errorf("failed to describe RDS instances: %v", err)
+		}
+
+		for _, instance := range page.DBInstances {
+			instanceClass := aws.ToString(instance.DBInstanceClass)
+			engine := aws.ToString(instance.Engine)
+			multiAZ := aws.ToBool(instance.MultiAZ)
+			price := func(instanceClass string) (float64, error) {
+				return getRDSOnDemandPrice(ctx, cfg, region, instanceClass, engine, aws.ToString(instance.LicenseModel), multiAZ)
+			}
+
+			target, reason := "", ""
+			if successor, ok := rightsizing.SuccessorType(instanceClass); ok {
+				target, reason = successor, "Previous-generation instance class"
+			} else if graviton, ok := rightsizing.GravitonType(instanceClass); ok && gravitonRDSEngines[engine] {
+				target, reason = graviton, "Graviton equivalent is cheaper for "+engine
+			} else {
+				continue
+			}
+
+			if optimization, ok := instanceModernization("rds:instance", aws.ToString(instance.DBInstanceIdentifier), instanceClass, target, reason, price); ok {
+				optimizations = append(optimizations, optimization)
+			}
+		}
+	}
+
+	return optimizations, nil
+}
+
+// instanceModernization prices the current and target types and returns an optimization when the
+// target is cheaper.
+func instanceModernization(resourceType, resourceID, current, target, reason string, price func(string) (float64, error)) (models.Optimization, bool) {
+	currentPrice, err := price(current)
+	if err != nil {
+		log.Printf("No on-demand price for %s: %v", current, err)
+		return models.Optimization{}, false
+	}
+	targetPrice, err := price(target)
+	if err != nil {
+		log.Printf("No on-demand price for %s: %v", target, err)
+		return models.Optimization{}, false
+	}
+	if targetPrice >= currentPrice {
+		return models.Optimization{}, false
+	}
+
+	return models.Optimization{
+		Category:                 "modernization",
+		ResourceType:             resourceType,
+		ResourceID:               resourceID,
+		CurrentConfiguration:     current,
+		RecommendedConfiguration: target,
+		Reason:                   reason,
+		EstimatedMonthlySavings:  (currentPrice - targetPrice) * hoursPerMonth,
+	}, true
+}
@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// NAT Gateways below both thresholds over the window are reported as idle.
+const (
+	natIdleBytesOut    = 100 * 1024 * 1024 // 100 MiB sent to destinations
+	natIdleConnections = 1                 // peak concurrent connections
+)
+
+// ListUnusedNATGateways identifies available NAT Gateways that no associated route table sends
+// traffic to, or that carried near-zero traffic between start and end.
+func ListUnusedNATGateways(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	ec2Client := ec2.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	unusedGateways := []models.UnusedResource{}
+
+	routed, err := getRoutedNATGateways(ctx, ec2Client)
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := ec2.NewDescribeNatGatewaysPaginator(ec2Client, &ec2.DescribeNatGatewaysInput{
+		Filter: []types.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []string{string(types.NatGatewayStateAvailable)},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe NAT Gateways: %v", err)
+			return nil, fmt.Errorf("failed to describe NAT Gateways: %v", err)
+		}
+
+		for _, gateway := range page.NatGateways {
+			gatewayID := aws.ToString(gateway.NatGatewayId)
+			dimensions := []cwtypes.Dimension{{Name: aws.String("NatGatewayId"), Value: aws.String(gatewayID)}}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("bytes_out", "AWS/NATGateway", "BytesOutToDestination", "Sum", dimensions, 86400, true),
+				metricQuery("connections", "AWS/NATGateway", "ActiveConnectionCount", "Maximum", dimensions, 86400, true),
+				metricQuery("bytes_in_source", "AWS/NATGateway", "BytesInFromSource", "Sum", dimensions, 86400, true),
+				metricQuery("bytes_in_destination", "AWS/NATGateway", "BytesInFromDestination", "Sum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for NAT Gateway %s: %v", gatewayID, err)
+				continue
+			}
+
+			// Data processing is billed on bytes in either direction; scale the window to a month
+			processedGB := (sumValues(values["bytes_in_source"]) + sumValues(values["bytes_in_destination"])) / bytesPerGB
+			monthlyCost := natGatewayHourlyPrice * hoursPerMonth
+			if hours := end.Sub(start).Hours(); hours > 0 {
+				monthlyCost += processedGB * natGatewayPerGBPrice * hoursPerMonth / hours
+			}
+
+			reason := ""
+			switch {
+			case !routed[gatewayID]:
+				reason = fmt.Sprintf("No subnet route table in %s routes traffic to it", aws.ToString(gateway.VpcId))
+			case sumValues(values["bytes_out"]) < natIdleBytesOut && maxValue(values["connections"]) <= natIdleConnections:
+				reason = fmt.Sprintf("Near-zero traffic (%.1f MiB out, peak %d connections) for %d days",
+					sumValues(values["bytes_out"])/1024/1024, int(maxValue(values["connections"])), int(end.Sub(start).Hours()/24))
+			default:
+				continue
+			}
+
+			unusedGateways = append(unusedGateways, models.UnusedResource{
+				ResourceType:         "ec2:nat-gateway",
+				ResourceID:           gatewayID,
+				Reason:               reason,
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found unused NAT Gateway: %s (%s)", gatewayID, reason)
+		}
+	}
+
+	return unusedGateways, nil
+}
+
+// getRoutedNATGateways returns the IDs of NAT Gateways targeted by a route in a route table that
+// is associated with a subnet or is its VPC's main route table.
+func getRoutedNATGateways(ctx context.Context, client *ec2.Client) (map[string]bool, error) {
+	routed := make(map[string]bool)
+	paginator := ec2.NewDescribeRouteTablesPaginator(client, &ec2.DescribeRouteTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe route tables: %v", err)
+			return nil, fmt.Errorf("failed to describe route tables: %v", err)
+		}
+
+		for _, routeTable := range page.RouteTables {
+			associated := false
+			for _, association := range routeTable.Associations {
+				if association.SubnetId != nil || aws.ToBool(association.Main) {
+					associated = true
+				}
+			}
+			if !associated {
+				continue
+			}
+			for _, route := range routeTable.Routes {
+				if route.NatGatewayId != nil {
+					routed[aws.ToString(route.NatGatewayId)] = true
+				}
+			}
+		}
+	}
+	return routed, nil
+}
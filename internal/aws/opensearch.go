@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// openSearchDescribeBatch is the most domains DescribeDomains accepts per call.
+const openSearchDescribeBatch = 5
+
+// ListUnusedOpenSearchDomains identifies OpenSearch domains with no search or indexing activity
+// between start and end.
+func ListUnusedOpenSearchDomains(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := opensearch.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	unusedDomains := []models.UnusedResource{}
+
+	namesResult, err := client.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
+	if err != nil {
+		log.Printf("Failed to list OpenSearch domains: %v", err)
+		return nil, fmt.Errorf("failed to list OpenSearch domains: %v", err)
+	}
+	names := []string{}
+	for _, domain := range namesResult.DomainNames {
+		names = append(names, aws.ToString(domain.DomainName))
+	}
+
+	for i := 0; i < len(names); i += openSearchDescribeBatch {
+		batch := names[i:min(i+openSearchDescribeBatch, len(names))]
+		result, err := client.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{DomainNames: batch})
+		if err != nil {
+			log.Printf("Failed to describe OpenSearch domains %v: %v", batch, err)
+			return nil, fmt.Errorf("failed to describe OpenSearch domains: %v", err)
+		}
+
+		for _, domain := range result.DomainStatusList {
+			domainName := aws.ToString(domain.DomainName)
+			if aws.ToBool(domain.Deleted) || domain.ClusterConfig == nil {
+				continue
+			}
+
+			// Domain metrics are dimensioned by the owning account, taken from the ARN
+			arnParts := strings.Split(aws.ToString(domain.ARN), ":")
+			if len(arnParts) < 5 {
+				continue
+			}
+			dimensions := []cwtypes.Dimension{
+				{Name: aws.String("DomainName"), Value: aws.String(domainName)},
+				{Name: aws.String("ClientId"), Value: aws.String(arnParts[4])},
+			}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("search", "AWS/ES", "SearchRate", "Maximum", dimensions, 86400, true),
+				metricQuery("indexing", "AWS/ES", "IndexingRate", "Maximum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for OpenSearch domain %s: %v", domainName, err)
+				continue
+			}
+			if maxValue(values["search"]) > 0 || maxValue(values["indexing"]) > 0 {
+				continue
+			}
+
+			// Data, dedicated master and UltraWarm nodes are billed separately
+			nodes := domain.ClusterConfig
+			monthlyCost := openSearchNodeCost(ctx, cfg, region, nodes.InstanceType, nodes.InstanceCount) +
+				openSearchNodeCost(ctx, cfg, region, nodes.DedicatedMasterType, nodes.DedicatedMasterCount) +
+				openSearchNodeCost(ctx, cfg, region, nodes.WarmType, nodes.WarmCount)
+
+			unusedDomains = append(unusedDomains, models.UnusedResource{
+				ResourceType:         "opensearch:domain",
+				ResourceID:           domainName,
+				Reason:               fmt.Sprintf("No search or indexing requests for %d days", int(end.Sub(start).Hours()/24)),
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found unused OpenSearch domain: %s", domainName)
+		}
+	}
+
+	return unusedDomains, nil
+}
+
+// openSearchNodeCost estimates the monthly cost of count OpenSearch nodes of a type, e.g.
+// "r6g.large.search". It returns 0 for unset node types or unknown prices.
+func openSearchNodeCost[T ~string](ctx context.Context, cfg *config.Config, region string, nodeType T, count *int32) float64 {
+	if nodeType == "" || aws.ToInt32(count) == 0 {
+		return 0
+	}
+	nodePrice, err := getNodeOnDemandPrice(ctx, cfg, "AmazonES", region, string(nodeType), nil)
+	if err != nil {
+		log.Printf("No on-demand price for OpenSearch node type %s: %v", nodeType, err)
+		return 0
+	}
+	return nodePrice * float64(aws.ToInt32(count)) * hoursPerMonth
+}
@@ -0,0 +1,215 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+)
+
+// pricingRegion hosts the AWS Price List API endpoint.
+const pricingRegion = "us-east-1"
+
+// onDemandPrices caches hourly on-demand prices by service and product filters.
+var onDemandPrices = struct {
+	sync.Mutex
+	prices map[string]float64
+}{prices: make(map[string]float64)}
+
+// rdsPricingEngines maps RDS engine names to Price List databaseEngine values.
+var rdsPricingEngines = map[string]string{
+	"mysql":             "MySQL",
+	"postgres":          "PostgreSQL",
+	"mariadb":           "MariaDB",
+	"aurora-mysql":      "Aurora MySQL",
+	"aurora-postgresql": "Aurora PostgreSQL",
+	"oracle":            "Oracle",
+	"sqlserver":         "SQL Server",
+	"db2":               "Db2",
+}
+
+// getEC2OnDemandPrice returns the hourly on-demand USD price of a shared-tenancy EC2 instance type.
+func getEC2OnDemandPrice(ctx context.Context, cfg *config.Config, region, instanceType, platform string) (float64, error) {
+	operatingSystem := "Linux"
+	switch {
+	case strings.Contains(platform, "Windows"):
+		operatingSystem = "Windows"
+	case strings.Contains(platform, "Red Hat"):
+		operatingSystem = "RHEL"
+	case strings.Contains(platform, "SUSE"):
+		operatingSystem = "SUSE"
+	}
+	return getOnDemandPrice(ctx, cfg, "AmazonEC2", map[string]string{
+		"regionCode":      region,
+		"instanceType":    instanceType,
+		"operatingSystem": operatingSystem,
+		"tenancy":         "Shared",
+		"preInstalledSw":  "NA",
+		"capacitystatus":  "Used",
+		"licenseModel":    "No License required",
+	})
+}
+
+// rdsPricingEditions maps RDS engine names to Price List databaseEdition values, for engines
+// priced by edition.
+var rdsPricingEditions = map[string]string{
+	"oracle-ee":      "Enterprise",
+	"oracle-ee-cdb":  "Enterprise",
+	"oracle-se2":     "Standard Two",
+	"oracle-se2-cdb": "Standard Two",
+	"oracle-se1":     "Standard One",
+	"oracle-se":      "Standard",
+	"sqlserver-ee":   "Enterprise",
+	"sqlserver-se":   "Standard",
+	"sqlserver-web":  "Web",
+	"sqlserver-ex":   "Express",
+	"db2-ae":         "Advanced",
+	"db2-se":         "Standard",
+}
+
+// rdsPricingLicenseModels maps RDS license models to Price List licenseModel values.
+var rdsPricingLicenseModels = map[string]string{
+	"license-included":       "License included",
+	"bring-your-own-license": "Bring your own license",
+	"general-public-license": "No license required",
+	"postgresql-license":     "No license required",
+	"marketplace-license":    "License included", // Db2 licensed through AWS Marketplace
+}
+
+// getRDSOnDemandPrice returns the hourly on-demand USD price of an RDS instance class. Oracle,
+// SQL Server and Db2 are priced by the edition in engine and by licenseModel, e.g.
+// "license-included"; other engines need neither.
+func getRDSOnDemandPrice(ctx context.Context, cfg *config.Config, region, instanceClass, engine, licenseModel string, multiAZ bool) (float64, error) {
+	databaseEngine, ok := rdsPricingEngines[engine]
+	if !ok {
+		// Editions such as oracle-ee or sqlserver-se share their engine's pricing name
+		family, _, _ := strings.Cut(engine, "-")
+		databaseEngine, ok = rdsPricingEngines[family]
+		if !ok {
+			return 0, fmt.Errorf("no pricing engine mapping for RDS engine '%s'", engine)
+		}
+	}
+	deploymentOption := "Single-AZ"
+	if multiAZ {
+		deploymentOption = "Multi-AZ"
+	}
+	filters := map[string]string{
+		"regionCode":       region,
+		"instanceType":     instanceClass,
+		"databaseEngine":   databaseEngine,
+		"deploymentOption": deploymentOption,
+	}
+	if edition, ok := rdsPricingEditions[engine]; ok {
+		filters["databaseEdition"] = edition
+	}
+	if model, ok := rdsPricingLicenseModels[licenseModel]; ok {
+		filters["licenseModel"] = model
+	} else if _, ok := rdsPricingEditions[engine]; ok {
+		// Without the license model the lowest price would be bring-your-own-license
+		return 0, fmt.Errorf("no pricing license model mapping for RDS license model '%s'", licenseModel)
+	}
+	return getOnDemandPrice(ctx, cfg, "AmazonRDS", filters)
+}
+
+// getNodeOnDemandPrice returns the hourly on-demand USD price of one node of a managed service,
+// e.g. "cache.r6g.large" in AmazonElastiCache. Extra filters narrow products sharing a node type.
+func getNodeOnDemandPrice(ctx context.Context, cfg *config.Config, serviceCode, region, nodeType string, extra map[string]string) (float64, error) {
+	filters := map[string]string{
+		"regionCode":   region,
+		"instanceType": nodeType,
+	}
+	for field, value := range extra {
+		filters[field] = value
+	}
+	return getOnDemandPrice(ctx, cfg, serviceCode, filters)
+}
+
+// getOnDemandPrice queries the Price List API for products matching filters and returns the
+// lowest non-zero hourly USD on-demand price among them.
+func getOnDemandPrice(ctx context.Context, cfg *config.Config, serviceCode string, filters map[string]string) (float64, error) {
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	key := serviceCode
+	input := &pricing.GetProductsInput{ServiceCode: aws.String(serviceCode)}
+	for _, field := range fields {
+		key += "|" + field + "=" + filters[field]
+		input.Filters = append(input.Filters, types.Filter{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String(field),
+			Value: aws.String(filters[field]),
+		})
+	}
+
+	onDemandPrices.Lock()
+	price, ok := onDemandPrices.prices[key]
+	onDemandPrices.Unlock()
+	if ok {
+		return price, nil
+	}
+
+	client := pricing.NewFromConfig(cfg.AWSConfig, func(o *pricing.Options) {
+		o.Region = pricingRegion
+	})
+	paginator := pricing.NewGetProductsPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to get %s prices for %v: %v", serviceCode, filters, err)
+			return 0, fmt.Errorf("failed to fetch prices: %v", err)
+		}
+		for _, item := range page.PriceList {
+			if hourly := parseOnDemandHourlyPrice(item); hourly > 0 && (price == 0 || hourly < price) {
+				price = hourly
+			}
+		}
+	}
+	if price == 0 {
+		return 0, fmt.Errorf("no on-demand price found for %s %v", serviceCode, filters)
+	}
+
+	onDemandPrices.Lock()
+	onDemandPrices.prices[key] = price
+	onDemandPrices.Unlock()
+	return price, nil
+}
+
+// parseOnDemandHourlyPrice extracts the hourly USD price from a Price List product document.
+func parseOnDemandHourlyPrice(document string) float64 {
+	var product struct {
+		Terms struct {
+			OnDemand map[string]struct {
+				PriceDimensions map[string]struct {
+					Unit         string            `json:"unit"`
+					PricePerUnit map[string]string `json:"pricePerUnit"`
+				} `json:"priceDimensions"`
+			} `json:"OnDemand"`
+		} `json:"terms"`
+	}
+	if err := json.Unmarshal([]byte(document), &product); err != nil {
+		return 0
+	}
+	for _, term := range product.Terms.OnDemand {
+		for _, dimension := range term.PriceDimensions {
+			if dimension.Unit != "Hrs" {
+				continue
+			}
+			if price, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64); err == nil && price > 0 {
+				return price
+			}
+		}
+	}
+	return 0
+}
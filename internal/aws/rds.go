@@ -10,12 +10,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/deepanshumishra/devcost-api/internal/config"
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
 
 // ListUnusedRDSResources identifies unused RDS instances.
-func ListUnusedRDSResources(cfg *config.Config, start, end time.Time, unusedForDays int) ([]models.UnusedResource, error) {
+func ListUnusedRDSResources(ctx context.Context, cfg *config.Config, start, end time.Time, unusedForDays int) ([]models.UnusedResource, error) {
 	// Initialize RDS client
 	client := rds.NewFromConfig(cfg.AWSConfig)
 
@@ -24,20 +25,29 @@ func ListUnusedRDSResources(cfg *config.Config, start, end time.Time, unusedForD
 
 	// List RDS instances
 	input := &rds.DescribeDBInstancesInput{}
-	result, err := client.DescribeDBInstances(context.TODO(), input)
+	result, err := client.DescribeDBInstances(ctx, input)
 	if err != nil {
 		log.Printf("Failed to describe RDS instances: %v", err)
 		return unusedResources, err
 	}
 
+	// Stopped instances are started again automatically 7 days after they were stopped
+	stopTimes := getRDSStopTimes(ctx, client, rdstypes.SourceTypeDbInstance)
+
 	// Check for stopped or idle RDS instances
 	for _, db := range result.DBInstances {
+		// Aurora instances are reported with their cluster by ListUnusedAuroraClusters
+		if db.DBClusterIdentifier != nil {
+			continue
+		}
+
 		// Stopped instances
 		if aws.ToString(db.DBInstanceStatus) == "stopped" {
 			unusedResources = append(unusedResources, models.UnusedResource{
-				ResourceType: "rds:instance",
-				ResourceID:   aws.ToString(db.DBInstanceIdentifier),
-				Reason:       "Stopped",
+				ResourceType:         "rds:instance",
+				ResourceID:           aws.ToString(db.DBInstanceIdentifier),
+				Reason:               stoppedRDSReason(stopTimes[aws.ToString(db.DBInstanceIdentifier)]),
+				EstimatedMonthlyCost: float64(aws.ToInt32(db.AllocatedStorage)) * rdsStoragePerGBMonthPrice,
 			})
 			log.Printf("Found unused RDS instance (stopped): %s", aws.ToString(db.DBInstanceIdentifier))
 			continue
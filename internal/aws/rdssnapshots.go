@@ -0,0 +1,109 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ListUnusedRDSSnapshots identifies manual DB snapshots and DB cluster snapshots older than
+// retentionDays or whose source instance or cluster no longer exists. Automated snapshots expire
+// with their retention period and are not reported.
+func ListUnusedRDSSnapshots(ctx context.Context, cfg *config.Config, retentionDays int) ([]models.UnusedResource, error) {
+	client := rds.NewFromConfig(cfg.AWSConfig)
+	unusedSnapshots := []models.UnusedResource{}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	instances := make(map[string]bool)
+	instancePaginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for instancePaginator.HasMorePages() {
+		page, err := instancePaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS instances: %v", err)
+			return nil, fmt.Errorf("failed to describe RDS instances: %v", err)
+		}
+		for _, instance := range page.DBInstances {
+			instances[aws.ToString(instance.DBInstanceIdentifier)] = true
+		}
+	}
+
+	snapshotPaginator := rds.NewDescribeDBSnapshotsPaginator(client, &rds.DescribeDBSnapshotsInput{SnapshotType: aws.String("manual")})
+	for snapshotPaginator.HasMorePages() {
+		page, err := snapshotPaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS snapshots: %v", err)
+			return nil, fmt.Errorf("failed to describe RDS snapshots: %v", err)
+		}
+
+		for _, snapshot := range page.DBSnapshots {
+			snapshotID := aws.ToString(snapshot.DBSnapshotIdentifier)
+			reason := staleRDSSnapshotReason(aws.ToString(snapshot.DBInstanceIdentifier), instances, aws.ToTime(snapshot.SnapshotCreateTime), cutoff, retentionDays)
+			if reason == "" {
+				continue
+			}
+			unusedSnapshots = append(unusedSnapshots, models.UnusedResource{
+				ResourceType:         "rds:snapshot",
+				ResourceID:           snapshotID,
+				Reason:               reason,
+				EstimatedMonthlyCost: float64(aws.ToInt32(snapshot.AllocatedStorage)) * rdsBackupPerGBMonthPrice,
+			})
+			log.Printf("Found unused RDS snapshot: %s", snapshotID)
+		}
+	}
+
+	clusters := make(map[string]bool)
+	clusterPaginator := rds.NewDescribeDBClustersPaginator(client, &rds.DescribeDBClustersInput{})
+	for clusterPaginator.HasMorePages() {
+		page, err := clusterPaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS clusters: %v", err)
+			return nil, fmt.Errorf("failed to describe RDS clusters: %v", err)
+		}
+		for _, cluster := range page.DBClusters {
+			clusters[aws.ToString(cluster.DBClusterIdentifier)] = true
+		}
+	}
+
+	clusterSnapshotPaginator := rds.NewDescribeDBClusterSnapshotsPaginator(client, &rds.DescribeDBClusterSnapshotsInput{SnapshotType: aws.String("manual")})
+	for clusterSnapshotPaginator.HasMorePages() {
+		page, err := clusterSnapshotPaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS cluster snapshots: %v", err)
+			return nil, fmt.Errorf("failed to describe RDS cluster snapshots: %v", err)
+		}
+
+		for _, snapshot := range page.DBClusterSnapshots {
+			snapshotID := aws.ToString(snapshot.DBClusterSnapshotIdentifier)
+			reason := staleRDSSnapshotReason(aws.ToString(snapshot.DBClusterIdentifier), clusters, aws.ToTime(snapshot.SnapshotCreateTime), cutoff, retentionDays)
+			if reason == "" {
+				continue
+			}
+			unusedSnapshots = append(unusedSnapshots, models.UnusedResource{
+				ResourceType:         "rds:cluster-snapshot",
+				ResourceID:           snapshotID,
+				Reason:               reason,
+				EstimatedMonthlyCost: float64(aws.ToInt32(snapshot.AllocatedStorage)) * auroraBackupPerGBMonthPrice,
+			})
+			log.Printf("Found unused RDS cluster snapshot: %s", snapshotID)
+		}
+	}
+
+	return unusedSnapshots, nil
+}
+
+// staleRDSSnapshotReason returns why a manual snapshot is stale, or "" when it is not.
+func staleRDSSnapshotReason(sourceID string, sources map[string]bool, created, cutoff time.Time, retentionDays int) string {
+	if !sources[sourceID] {
+		return "Source " + sourceID + " no longer exists"
+	}
+	if created.Before(cutoff) {
+		return fmt.Sprintf("Older than %d days", retentionDays)
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/commitment"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// GetSavingsPlansPurchaseRecommendations returns Cost Explorer's Savings Plans purchase
+// recommendations for the plan type, term, payment option and lookback period.
+func GetSavingsPlansPurchaseRecommendations(ctx context.Context, cfg *config.Config, planType types.SupportedSavingsPlansType, term types.TermInYears, payment types.PaymentOption, lookback types.LookbackPeriodInDays) ([]models.CommitmentRecommendation, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetSavingsPlansPurchaseRecommendationInput{
+		SavingsPlansType:     planType,
+		TermInYears:          term,
+		PaymentOption:        payment,
+		LookbackPeriodInDays: lookback,
+	}
+
+	recommendations := []models.CommitmentRecommendation{}
+	for {
+		result, err := client.GetSavingsPlansPurchaseRecommendation(ctx, input)
+		if err != nil {
+			log.Printf("Failed to get Savings Plans purchase recommendations: %v", err)
+			return nil, fmt.Errorf("failed to fetch Savings Plans purchase recommendations: %v", err)
+		}
+
+		if recommendation := result.SavingsPlansPurchaseRecommendation; recommendation != nil {
+			for _, detail := range recommendation.SavingsPlansPurchaseRecommendationDetails {
+				recommendations = append(recommendations, models.CommitmentRecommendation{
+					CommitmentType:             "savings_plans",
+					Offering:                   string(planType),
+					Term:                       string(term),
+					PaymentOption:              string(payment),
+					LookbackPeriod:             string(lookback),
+					AccountID:                  aws.ToString(detail.AccountId),
+					HourlyCommitment:           parseCostExplorerAmount(detail.HourlyCommitmentToPurchase),
+					UpfrontCost:                parseCostExplorerAmount(detail.UpfrontCost),
+					EstimatedMonthlySavings:    parseCostExplorerAmount(detail.EstimatedMonthlySavingsAmount),
+					EstimatedSavingsPercentage: parseCostExplorerAmount(detail.EstimatedSavingsPercentage),
+					EstimatedUtilization:       parseCostExplorerAmount(detail.EstimatedAverageUtilization),
+					Currency:                   aws.ToString(detail.CurrencyCode),
+				})
+			}
+		}
+
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	log.Printf("Found %d Savings Plans purchase recommendations for %s %s %s", len(recommendations), planType, term, payment)
+	return recommendations, nil
+}
+
+// GetReservationPurchaseRecommendations returns Cost Explorer's Reserved Instance purchase
+// recommendations for a service, e.g. "Amazon Elastic Compute Cloud - Compute".
+func GetReservationPurchaseRecommendations(ctx context.Context, cfg *config.Config, service string, term types.TermInYears, payment types.PaymentOption, lookback types.LookbackPeriodInDays) ([]models.CommitmentRecommendation, error) {
+	client := costexplorer.NewFromConfig(cfg.AWSConfig)
+	input := &costexplorer.GetReservationPurchaseRecommendationInput{
+		Service:              aws.String(service),
+		TermInYears:          term,
+		PaymentOption:        payment,
+		LookbackPeriodInDays: lookback,
+	}
+
+	recommendations := []models.CommitmentRecommendation{}
+	for {
+		result, err := client.GetReservationPurchaseRecommendation(ctx, input)
+		if err != nil {
+			log.Printf("Failed to get reservation purchase recommendations for %s: %v", service, err)
+			return nil, fmt.Errorf("failed to fetch reservation purchase recommendations for '%s': %v", service, err)
+		}
+
+		for _, recommendation := range result.Recommendations {
+			for _, detail := range recommendation.RecommendationDetails {
+				instanceType, region := reservationInstance(detail.InstanceDetails)
+				recommendations = append(recommendations, models.CommitmentRecommendation{
+					CommitmentType:             "reservation",
+					Offering:                   service,
+					Term:                       string(recommendation.TermInYears),
+					PaymentOption:              string(recommendation.PaymentOption),
+					LookbackPeriod:             string(recommendation.LookbackPeriodInDays),
+					AccountID:                  aws.ToString(detail.AccountId),
+					InstanceType:               instanceType,
+					Region:                     region,
+					Quantity:                   parseCostExplorerAmount(detail.RecommendedNumberOfInstancesToPurchase),
+					UpfrontCost:                parseCostExplorerAmount(detail.UpfrontCost),
+					MonthlyRecurringCost:       parseCostExplorerAmount(detail.RecurringStandardMonthlyCost),
+					EstimatedMonthlySavings:    parseCostExplorerAmount(detail.EstimatedMonthlySavingsAmount),
+					EstimatedSavingsPercentage: parseCostExplorerAmount(detail.EstimatedMonthlySavingsPercentage),
+					EstimatedUtilization:       parseCostExplorerAmount(detail.AverageUtilization),
+					Currency:                   aws.ToString(detail.CurrencyCode),
+				})
+			}
+		}
+
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	log.Printf("Found %d reservation purchase recommendations for %s", len(recommendations), service)
+	return recommendations, nil
+}
+
+// reservationInstance extracts the instance or node type and region from reservation details.
+func reservationInstance(details *types.InstanceDetails) (string, string) {
+	switch {
+	case details == nil:
+		return "", ""
+	case details.EC2InstanceDetails != nil:
+		return aws.ToString(details.EC2InstanceDetails.InstanceType), aws.ToString(details.EC2InstanceDetails.Region)
+	case details.RDSInstanceDetails != nil:
+		return aws.ToString(details.RDSInstanceDetails.InstanceType), aws.ToString(details.RDSInstanceDetails.Region)
+	case details.ElastiCacheInstanceDetails != nil:
+		return aws.ToString(details.ElastiCacheInstanceDetails.NodeType), aws.ToString(details.ElastiCacheInstanceDetails.Region)
+	case details.ESInstanceDetails != nil:
+		return aws.ToString(details.ESInstanceDetails.InstanceClass) + "." + aws.ToString(details.ESInstanceDetails.InstanceSize), aws.ToString(details.ESInstanceDetails.Region)
+	case details.RedshiftInstanceDetails != nil:
+		return aws.ToString(details.RedshiftInstanceDetails.NodeType), aws.ToString(details.RedshiftInstanceDetails.Region)
+	case details.MemoryDBInstanceDetails != nil:
+		return aws.ToString(details.MemoryDBInstanceDetails.NodeType), aws.ToString(details.MemoryDBInstanceDetails.Region)
+	}
+	return "", ""
+}
+
+// SimulateCommitment models a hypothetical hourly commitment against the hourly on-demand spend
+// stored in ingested CUR data, optionally for one product code (e.g. AmazonEC2).
+func SimulateCommitment(cfg *config.Config, start, end time.Time, service string, hourlyCommitment float64, term string, discountRate float64) (models.CommitmentSimulation, error) {
+	if cfg.CURStore == nil || cfg.CURStore.Len() == 0 {
+		return models.CommitmentSimulation{}, fmt.Errorf("no CUR data has been ingested")
+	}
+	// The simulation allocates one value per hour of the range
+	if end.Sub(start) > commitment.MaxSimulationRange {
+		return models.CommitmentSimulation{}, fmt.Errorf("date range must not exceed 3 years")
+	}
+
+	// Only AWS on-demand usage is eligible; usage already covered by commitments and usage
+	// imported from other clouds are excluded
+	hourly := make([]float64, int(end.Sub(start)/time.Hour))
+	currency := ""
+	var mixed string
+	cfg.CURStore.Scan(start, end, func(item cur.LineItem) {
+		if item.Provider != "AWS" || item.LineItemType != "Usage" || (service != "" && item.ProductCode != service) {
+			return
+		}
+		itemCurrency := item.Currency
+		if itemCurrency == "" {
+			itemCurrency = "USD"
+		}
+		if currency == "" {
+			currency = itemCurrency
+		} else if itemCurrency != currency {
+			mixed = itemCurrency
+			return
+		}
+		commitment.SpreadHourly(hourly, start, item.UsageStart, item.UsageEnd, item.UnblendedCost)
+	})
+	if mixed != "" {
+		return models.CommitmentSimulation{}, fmt.Errorf("on-demand usage is billed in both %s and %s", currency, mixed)
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+
+	simulation := commitment.Simulate(hourly, hourlyCommitment, discountRate)
+	simulation.Start = start.Format("2006-01-02")
+	simulation.End = end.Format("2006-01-02")
+	simulation.Service = service
+	simulation.Term = term
+	simulation.Currency = currency
+
+	log.Printf("Simulated %.2f/hour %s commitment over %d hours: savings %.2f %s", hourlyCommitment, term, simulation.Hours, simulation.EstimatedSavings, currency)
+	return simulation, nil
+}
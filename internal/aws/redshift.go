@@ -0,0 +1,96 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// redshiftPauseIdleFraction is the share of hours without connections above which an RA3 or DC2
+// cluster is reported as a candidate for a pause and resume schedule.
+const redshiftPauseIdleFraction = 0.5
+
+// ListUnusedRedshiftClusters identifies available Redshift clusters with no database connections
+// between start and end, and pausable clusters idle for most hours. For the latter, the estimated
+// cost is the share of the cluster's cost spent on idle hours.
+func ListUnusedRedshiftClusters(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := redshift.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	unusedClusters := []models.UnusedResource{}
+
+	paginator := redshift.NewDescribeClustersPaginator(client, &redshift.DescribeClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Redshift clusters: %v", err)
+			return nil, fmt.Errorf("failed to describe Redshift clusters: %v", err)
+		}
+
+		for _, cluster := range page.Clusters {
+			clusterID := aws.ToString(cluster.ClusterIdentifier)
+			if aws.ToString(cluster.ClusterStatus) != "available" {
+				continue
+			}
+
+			dimensions := []cwtypes.Dimension{{Name: aws.String("ClusterIdentifier"), Value: aws.String(clusterID)}}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("connections", "AWS/Redshift", "DatabaseConnections", "Maximum", dimensions, 3600, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Redshift cluster %s: %v", clusterID, err)
+				continue
+			}
+			connections := values["connections"]
+			if len(connections) == 0 {
+				continue
+			}
+			idleHours := 0
+			for _, value := range connections {
+				if value == 0 {
+					idleHours++
+				}
+			}
+			idleFraction := float64(idleHours) / float64(len(connections))
+
+			nodeType := aws.ToString(cluster.NodeType)
+			nodes := aws.ToInt32(cluster.NumberOfNodes)
+			nodePrice, err := getNodeOnDemandPrice(ctx, cfg, "AmazonRedshift", region, nodeType, nil)
+			if err != nil {
+				log.Printf("No on-demand price for Redshift node type %s: %v", nodeType, err)
+			}
+			monthlyCost := nodePrice * float64(nodes) * hoursPerMonth
+
+			pausable := strings.HasPrefix(nodeType, "ra3.") || strings.HasPrefix(nodeType, "dc2.")
+			var reason string
+			switch {
+			case idleHours == len(connections):
+				reason = fmt.Sprintf("No database connections for %d days (%d x %s)", int(end.Sub(start).Hours()/24), nodes, nodeType)
+			case pausable && idleFraction >= redshiftPauseIdleFraction:
+				reason = fmt.Sprintf("No connections in %.0f%% of hours; pause it on a schedule", idleFraction*100)
+				monthlyCost *= idleFraction
+			default:
+				continue
+			}
+
+			unusedClusters = append(unusedClusters, models.UnusedResource{
+				ResourceType:         "redshift:cluster",
+				ResourceID:           clusterID,
+				Reason:               reason,
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found unused Redshift cluster: %s (%s)", clusterID, reason)
+		}
+	}
+
+	return unusedClusters, nil
+}
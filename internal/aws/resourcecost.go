@@ -0,0 +1,198 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// resourceLevelWindow is how far back Cost Explorer keeps resource-level data.
+const resourceLevelWindow = 14 * 24 * time.Hour
+
+// noResourceID is the RESOURCE_ID Cost Explorer reports for costs not tied to a resource.
+const noResourceID = "NoResourceId"
+
+// resourceLevelServices are the services whose resource-level data is queried from Cost
+// Explorer. GetCostAndUsageWithResources requires a SERVICE filter; EC2 compute is always
+// available, the others only once multi-service resource-level data is enabled.
+var resourceLevelServices = map[string]bool{
+	"Amazon Elastic Compute Cloud - Compute": true,
+	"Amazon Relational Database Service":     true,
+	"Amazon Simple Storage Service":          true,
+	"Amazon DynamoDB":                        true,
+	"Amazon ElastiCache":                     true,
+	"AWS Lambda":                             true,
+}
+
+// costAndUsageWithResourcesAPI is the part of the Cost Explorer client used for resource-level costs.
+type costAndUsageWithResourcesAPI interface {
+	GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error)
+}
+
+// tagServiceCosts is the Cost Explorer total of one tag value, broken down by service.
+type tagServiceCosts struct {
+	Currency string
+	Services map[string]float64
+}
+
+// getTagResourceCosts fetches per-resource costs for each tag value in tags. Ranges within
+// the last 14 days come from Cost Explorer resource-level data; longer ranges are read from
+// ingested CUR data. It returns nil when neither source is available.
+func getTagResourceCosts(ctx context.Context, cfg *config.Config, tagKey string, tags map[string]tagServiceCosts, start, end time.Time, metric cur.CostMetric) map[string][]models.ResourceCost {
+	if !start.Before(time.Now().Add(-resourceLevelWindow)) {
+		return getTagResourceCostsFromCostExplorer(ctx, costexplorer.NewFromConfig(cfg.AWSConfig), tagKey, tags, start, end, metric)
+	}
+	if cfg.CURStore != nil && cfg.CURStore.Len() > 0 && metric != cur.MetricNetAmortized {
+		currencies := make(map[string]string, len(tags))
+		for tagValue, costs := range tags {
+			currencies[tagValue] = costs.Currency
+		}
+		return getTagResourceCostsFromCUR(cfg.CURStore, tagKey, currencies, start, end, metric)
+	}
+	log.Printf("Resource-level costs for tag %s unavailable: range starts before the 14-day Cost Explorer window and no CUR data is ingested for metric %s", tagKey, metric)
+	return nil
+}
+
+// getTagResourceCostsFromCostExplorer queries GetCostAndUsageWithResources for each tag value
+// and resource-level service, grouped by resource ID. Other services, and services whose
+// resource-level data cannot be read, are reported with their service-level cost.
+func getTagResourceCostsFromCostExplorer(ctx context.Context, client costAndUsageWithResourcesAPI, tagKey string, tags map[string]tagServiceCosts, start, end time.Time, metric cur.CostMetric) map[string][]models.ResourceCost {
+	metricName := costExplorerMetrics[metric]
+	resourceCosts := make(map[string][]models.ResourceCost)
+
+	for tagValue, tagCosts := range tags {
+		costs := make(map[cur.ResourceKey]float64)
+		for serviceName, serviceCost := range tagCosts.Services {
+			if !resourceLevelServices[serviceName] {
+				costs[cur.ResourceKey{Service: serviceName}] += serviceCost
+				continue
+			}
+			serviceResources, err := getServiceResourceCosts(ctx, client, metricName, tagKey, tagValue, serviceName, start, end)
+			if err != nil {
+				log.Printf("Falling back to the service-level cost of %s for tag %s=%s: %v", serviceName, tagKey, tagValue, err)
+				costs[cur.ResourceKey{Service: serviceName}] += serviceCost
+				continue
+			}
+			for key, cost := range serviceResources {
+				costs[key] += cost
+			}
+		}
+
+		resourceCosts[tagValue] = toResourceCosts(costs)
+		log.Printf("Found %d resource cost entries for tag %s=%s", len(resourceCosts[tagValue]), tagKey, tagValue)
+	}
+
+	return resourceCosts
+}
+
+// getServiceResourceCosts sums one service's costs per resource ID for a tag value.
+func getServiceResourceCosts(ctx context.Context, client costAndUsageWithResourcesAPI, metricName, tagKey, tagValue, serviceName string, start, end time.Time) (map[cur.ResourceKey]float64, error) {
+	costs := make(map[cur.ResourceKey]float64)
+	input := &costexplorer.GetCostAndUsageWithResourcesInput{
+		TimePeriod: &types.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+		Granularity: types.GranularityDaily,
+		Metrics:     []string{metricName},
+		Filter: &types.Expression{
+			And: []types.Expression{
+				{
+					Tags: &types.TagValues{
+						Key:    aws.String(tagKey),
+						Values: []string{tagValue},
+					},
+				},
+				{
+					Dimensions: &types.DimensionValues{
+						Key:    types.DimensionService,
+						Values: []string{serviceName},
+					},
+				},
+			},
+		},
+		GroupBy: []types.GroupDefinition{
+			{
+				Type: types.GroupDefinitionTypeDimension,
+				Key:  aws.String("RESOURCE_ID"),
+			},
+		},
+	}
+
+	for {
+		result, err := client.GetCostAndUsageWithResources(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s resource costs for tag '%s': %v", serviceName, tagKey, err)
+		}
+
+		for _, group := range result.ResultsByTime {
+			for _, costGroup := range group.Groups {
+				costAmount, err := strconv.ParseFloat(aws.ToString(costGroup.Metrics[metricName].Amount), 64)
+				if err != nil {
+					log.Printf("Failed to parse resource cost for tag %s=%s, keys %v: %v", tagKey, tagValue, costGroup.Keys, err)
+					continue
+				}
+				resourceID := costGroup.Keys[0]
+				if resourceID == noResourceID {
+					resourceID = ""
+				}
+				costs[cur.ResourceKey{Service: serviceName, ResourceID: resourceID}] += costAmount
+			}
+		}
+
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+	return costs, nil
+}
+
+// getTagResourceCostsFromCUR reads per-resource costs from ingested CUR data. The store also
+// holds FOCUS imports from other clouds, so only AWS line items in the currency Cost Explorer
+// reported for the tag value are kept; anything else would not add up to the tag's total.
+func getTagResourceCostsFromCUR(store *cur.Store, tagKey string, currencies map[string]string, start, end time.Time, metric cur.CostMetric) map[string][]models.ResourceCost {
+	costs := store.ResourceCostsByTag(tagKey, start, end, metric)
+	resourceCosts := make(map[string][]models.ResourceCost)
+	for tagValue, resources := range costs {
+		currency, ok := currencies[tagValue]
+		if !ok {
+			continue
+		}
+		for key := range resources {
+			if key.Provider != "AWS" || key.Currency != currency {
+				delete(resources, key)
+			}
+		}
+		resourceCosts[tagValue] = toResourceCosts(resources)
+	}
+	return resourceCosts
+}
+
+// toResourceCosts converts aggregated per-resource costs to the API model.
+func toResourceCosts(costs map[cur.ResourceKey]float64) []models.ResourceCost {
+	resources := []models.ResourceCost{}
+	for key, cost := range costs {
+		// Resource types are only derived for AWS; other clouds report the service name
+		resourceType := key.Service
+		if key.ResourceID != "" && (key.Provider == "" || key.Provider == "AWS") {
+			resourceType = getResourceTypeFromID(key.ResourceID, key.Service)
+		}
+		resources = append(resources, models.ResourceCost{
+			Provider:     key.Provider,
+			ResourceType: resourceType,
+			ResourceID:   key.ResourceID,
+			Cost:         cost,
+		})
+	}
+	return resources
+}
@@ -0,0 +1,106 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+)
+
+func TestTagResourceCostsFromCURKeepsOnlyAWSInTagCurrency(t *testing.T) {
+	usageStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	tags := map[string]string{"project": "dev-cluster"}
+	store, _ := cur.NewStore("")
+	store.Replace("cur", "1", []cur.LineItem{{Provider: "AWS", UsageStart: usageStart, ProductCode: "AmazonEC2", ResourceID: "i-0abc", UnblendedCost: 2, Tags: tags}})
+	store.Replace("focus/azure.csv", "1", []cur.LineItem{{Provider: "Microsoft", UsageStart: usageStart, ProductCode: "Virtual Machines", ResourceID: "vm", UnblendedCost: 3, Currency: "EUR", Tags: tags}})
+	store.Replace("focus/gcp.csv", "1", []cur.LineItem{{Provider: "Google Cloud", UsageStart: usageStart, ProductCode: "Compute Engine", ResourceID: "vm", UnblendedCost: 4, Currency: "USD", Tags: tags}})
+
+	costs := getTagResourceCostsFromCUR(store, "project", map[string]string{"dev-cluster": "USD"}, usageStart, usageStart.AddDate(0, 0, 1), cur.MetricUnblended)
+	resources := costs["dev-cluster"]
+	if len(resources) != 1 || resources[0].ResourceID != "i-0abc" || resources[0].Cost != 2 {
+		t.Errorf("expected only the AWS resource i-0abc at 2 USD, got %+v", resources)
+	}
+}
+
+// stubCostExplorer answers GetCostAndUsageWithResources with canned groups per service and
+// fails for services it has none for, as Cost Explorer does without resource-level data.
+type stubCostExplorer struct {
+	groups map[string][]types.Group
+	inputs []*costexplorer.GetCostAndUsageWithResourcesInput
+}
+
+func (s *stubCostExplorer) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	s.inputs = append(s.inputs, params)
+	var service string
+	for _, expression := range params.Filter.And {
+		if expression.Dimensions != nil && expression.Dimensions.Key == types.DimensionService {
+			service = expression.Dimensions.Values[0]
+		}
+	}
+	if service == "" {
+		return nil, errors.New("ValidationException: a SERVICE filter is required")
+	}
+	groups, ok := s.groups[service]
+	if !ok {
+		return nil, errors.New("DataUnavailableException: resource-level data is not enabled")
+	}
+	return &costexplorer.GetCostAndUsageWithResourcesOutput{
+		ResultsByTime: []types.ResultByTime{{Groups: groups}},
+	}, nil
+}
+
+func TestTagResourceCostsFromCostExplorer(t *testing.T) {
+	const ec2 = "Amazon Elastic Compute Cloud - Compute"
+	group := func(resourceID, amount string) types.Group {
+		return types.Group{
+			Keys:    []string{resourceID},
+			Metrics: map[string]types.MetricValue{"UnblendedCost": {Amount: aws.String(amount), Unit: aws.String("USD")}},
+		}
+	}
+	client := &stubCostExplorer{groups: map[string][]types.Group{
+		ec2: {group("i-0abc", "1.5"), group(noResourceID, "0.5")},
+	}}
+	tags := map[string]tagServiceCosts{
+		"dev-cluster": {Currency: "USD", Services: map[string]float64{
+			ec2:                                  2,
+			"Amazon Relational Database Service": 3,
+			"AWS Key Management Service":         1,
+		}},
+	}
+
+	end := time.Now()
+	costs := getTagResourceCostsFromCostExplorer(context.Background(), client, "project", tags, end.AddDate(0, 0, -7), end, cur.MetricUnblended)
+
+	// EC2 and RDS are queried with a SERVICE filter; KMS has no resource-level data
+	if len(client.inputs) != 2 {
+		t.Errorf("expected 2 resource-level queries, got %d", len(client.inputs))
+	}
+	got := make(map[string]float64)
+	var total float64
+	for _, resource := range costs["dev-cluster"] {
+		got[resource.ResourceType+"|"+resource.ResourceID] = resource.Cost
+		total += resource.Cost
+	}
+	want := map[string]float64{
+		getResourceTypeFromID("i-0abc", ec2) + "|i-0abc": 1.5,
+		ec2 + "|":                             0.5,
+		"Amazon Relational Database Service|": 3,
+		"AWS Key Management Service|":         1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for key, cost := range want {
+		if got[key] != cost {
+			t.Errorf("%s: expected %.2f, got %.2f", key, cost, got[key])
+		}
+	}
+	if total != 6 {
+		t.Errorf("expected resources to add up to the tag total of 6, got %.2f", total)
+	}
+}
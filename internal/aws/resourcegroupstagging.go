@@ -96,4 +96,33 @@ func convertTags(tags []types.Tag) map[string]string {
 		tagMap[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
 	return tagMap
-}
\ No newline at end of file
+}
+
+// getResourceTypeFromID derives a resource type from a billing resource ID, which is
+// an ARN, a bare EC2-style ID (e.g. "i-0abc", "vol-0abc") or a name such as an S3 bucket.
+// It falls back to the billing service name when the type cannot be determined.
+func getResourceTypeFromID(resourceID, service string) string {
+	if strings.HasPrefix(resourceID, "arn:") {
+		return getResourceTypeFromARN(resourceID)
+	}
+	for prefix, resourceType := range resourceIDPrefixes {
+		if strings.HasPrefix(resourceID, prefix) {
+			return resourceType
+		}
+	}
+	if service == "Amazon Simple Storage Service" || service == "AmazonS3" {
+		return "s3:bucket"
+	}
+	return service
+}
+
+// resourceIDPrefixes maps bare resource ID prefixes to resource types.
+var resourceIDPrefixes = map[string]string{
+	"i-":        "ec2:instance",
+	"vol-":      "ebs:volume",
+	"snap-":     "ebs:snapshot",
+	"ami-":      "ec2:image",
+	"eipalloc-": "ec2:elastic-ip",
+	"nat-":      "ec2:nat-gateway",
+	"vpce-":     "ec2:vpc-endpoint",
+}
@@ -6,116 +6,70 @@ import (
 	"log"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/deepanshumishra/devcost-api/internal/config"
 	"github.com/deepanshumishra/devcost-api/internal/models"
 )
 
+// unusedResourceScan is one detector run by ListUnusedResources.
+type unusedResourceScan struct {
+	name string
+	run  func() ([]models.UnusedResource, error)
+}
+
+// unusedResourceScans lists the detectors ListUnusedResources runs, in report order.
+func unusedResourceScans(ctx context.Context, cfg *config.Config, start, end time.Time, unusedForDays int) []unusedResourceScan {
+	// Instances judged with their Auto Scaling group are skipped by the EC2 instance check; if the
+	// group scan fails they are checked individually
+	var groupedInstances map[string]bool
+	return []unusedResourceScan{
+		{"Auto Scaling groups", func() ([]models.UnusedResource, error) {
+			idleGroups, judged, err := listIdleAutoScalingGroups(ctx, cfg, start, end)
+			groupedInstances = judged
+			return idleGroups, err
+		}},
+		// EC2 instances, EBS volumes and Elastic IPs
+		{"EC2 resources", func() ([]models.UnusedResource, error) { return ListUnusedEC2Resources(cfg, start, end, groupedInstances) }},
+		{"RDS resources", func() ([]models.UnusedResource, error) { return ListUnusedRDSResources(ctx, cfg, start, end, unusedForDays) }},
+		{"Aurora clusters", func() ([]models.UnusedResource, error) { return ListUnusedAuroraClusters(ctx, cfg, start, end) }},
+		{"RDS snapshots", func() ([]models.UnusedResource, error) { return ListUnusedRDSSnapshots(ctx, cfg, unusedForDays) }},
+		{"Bedrock resources", func() ([]models.UnusedResource, error) { return ListUnusedBedrockResources(ctx, cfg, start, end, unusedForDays) }},
+		{"SageMaker resources", func() ([]models.UnusedResource, error) { return ListUnusedSageMakerResources(ctx, cfg, start, end) }},
+		{"Lambda resources", func() ([]models.UnusedResource, error) { return ListUnusedLambdaResources(cfg, start, end, unusedForDays) }},
+		{"DynamoDB resources", func() ([]models.UnusedResource, error) { return ListUnusedDynamoDBResources(cfg, start, end, unusedForDays) }},
+		{"EBS snapshots and AMIs", func() ([]models.UnusedResource, error) { return ListUnusedSnapshotsAndAMIs(ctx, cfg, unusedForDays) }},
+		{"load balancers", func() ([]models.UnusedResource, error) { return ListUnusedLoadBalancers(ctx, cfg, start, end) }},
+		{"VPC networking", func() ([]models.UnusedResource, error) { return ListUnusedVPCResources(ctx, cfg, start, end) }},
+		{"NAT Gateways", func() ([]models.UnusedResource, error) { return ListUnusedNATGateways(ctx, cfg, start, end) }},
+		{"ElastiCache clusters", func() ([]models.UnusedResource, error) { return ListUnusedElastiCacheClusters(ctx, cfg, start, end) }},
+		{"OpenSearch domains", func() ([]models.UnusedResource, error) { return ListUnusedOpenSearchDomains(ctx, cfg, start, end) }},
+		{"Redshift clusters", func() ([]models.UnusedResource, error) { return ListUnusedRedshiftClusters(ctx, cfg, start, end) }},
+		{"ECS resources", func() ([]models.UnusedResource, error) { return ListUnusedECSResources(ctx, cfg) }},
+		{"EKS resources", func() ([]models.UnusedResource, error) { return ListUnusedEKSResources(ctx, cfg, start, end) }},
+		{"ECR repositories", func() ([]models.UnusedResource, error) { return ListUnusedECRImages(ctx, cfg, unusedForDays) }},
+		{"CloudWatch log groups", func() ([]models.UnusedResource, error) { return ListUnusedLogGroups(ctx, cfg, start, end) }},
+		{"S3 buckets", func() ([]models.UnusedResource, error) { return ListUnusedS3Buckets(ctx, cfg, start, end) }},
+		{"Secrets Manager resources", func() ([]models.UnusedResource, error) { return ListUnusedSecrets(ctx, cfg, unusedForDays) }},
+		{"KMS keys", func() ([]models.UnusedResource, error) { return ListUnusedKMSKeys(ctx, cfg, unusedForDays) }},
+		{"Route 53 hosted zones", func() ([]models.UnusedResource, error) { return ListUnusedHostedZones(ctx, cfg, start, end) }},
+		{"private certificate authorities", func() ([]models.UnusedResource, error) { return ListUnusedPrivateCAs(ctx, cfg, unusedForDays) }},
+	}
+}
+
 // ListUnusedResources fetches all unused paid AWS resources.
 func ListUnusedResources(ctx context.Context, cfg *config.Config, start, end time.Time, unusedForDays int) ([]models.UnusedResource, error) {
 	// Initialize to avoid nil response
 	allResources := []models.UnusedResource{}
 	var errors []error
 
-	// Get unused EC2 resources (instances, EBS volumes, Elastic IPs)
-	ec2Resources, err := ListUnusedEC2Resources(cfg, start, end)
-	if err != nil {
-		log.Printf("Failed to list EC2 resources: %v", err)
-		errors = append(errors, err)
-	} else {
-		allResources = append(allResources, ec2Resources...)
-	}
-
-	// Get unused RDS resources
-	rdsResources, err := ListUnusedRDSResources(cfg, start, end, unusedForDays)
-	if err != nil {
-		log.Printf("Failed to list RDS resources: %v", err)
-		errors = append(errors, err)
-	} else {
-		allResources = append(allResources, rdsResources...)
-	}
-
-	// Get unused Bedrock resources
-	bedrockResources, err := ListUnusedBedrockResources(cfg, start, end, unusedForDays)
-	if err != nil {
-		log.Printf("Failed to list Bedrock resources: %v", err)
-		errors = append(errors, err)
-	} else {
-		allResources = append(allResources, bedrockResources...)
-	}
-
-	// Get unused Lambda resources
-	lambdaResources, err := ListUnusedLambdaResources(cfg, start, end, unusedForDays)
-	if err != nil {
-		log.Printf("Failed to list Lambda resources: %v", err)
-		errors = append(errors, err)
-	} else {
-		allResources = append(allResources, lambdaResources...)
-	}
-
-	// Get unused DynamoDB resources
-	dynamoDBResources, err := ListUnusedDynamoDBResources(cfg, start, end, unusedForDays)
-	if err != nil {
-		log.Printf("Failed to list DynamoDB resources: %v", err)
-		errors = append(errors, err)
-	} else {
-		allResources = append(allResources, dynamoDBResources...)
-	}
-
-	// Get unused Secrets Manager resources
-	secretResources, err := ListUnusedSecrets(ctx, cfg, unusedForDays)
-	if err != nil {
-		log.Printf("Failed to list Secrets Manager resources: %v", err)
-		errors = append(errors, err)
-	} else {
-		allResources = append(allResources, secretResources...)
-	}
-
-	// Get unused S3 buckets
-	s3Client := s3.NewFromConfig(cfg.AWSConfig)
-	cloudwatchClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
-	s3Resp, err := s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
-	if err != nil {
-		log.Printf("Failed to list S3 buckets: %v", err)
-		errors = append(errors, err)
-	} else {
-		for _, bucket := range s3Resp.Buckets {
-			metrics, err := cloudwatchClient.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
-				MetricDataQueries: []types.MetricDataQuery{
-					{
-						Id: aws.String("requests"),
-						MetricStat: &types.MetricStat{
-							Metric: &types.Metric{
-								Namespace:  aws.String("AWS/S3"),
-								MetricName: aws.String("AllRequests"),
-								Dimensions: []types.Dimension{
-									{Name: aws.String("BucketName"), Value: bucket.Name},
-									{Name: aws.String("FilterId"), Value: aws.String("EntireBucket")},
-								},
-							},
-							Period: aws.Int32(86400),
-							Stat:   aws.String("Sum"),
-						},
-					},
-				},
-				StartTime: aws.Time(start),
-				EndTime:   aws.Time(end),
-			})
-			if err != nil {
-				log.Printf("Failed to get metrics for S3 bucket %s: %v", *bucket.Name, err)
-				continue
-			}
-			if len(metrics.MetricDataResults) == 0 || len(metrics.MetricDataResults[0].Values) == 0 {
-				allResources = append(allResources, models.UnusedResource{
-					ResourceType: "s3:bucket",
-					ResourceID:   *bucket.Name,
-					Reason:       "No requests for " + fmt.Sprintf("%d days", unusedForDays),
-				})
-			}
+	// Run each detector, keeping partial results when some fail
+	for _, scan := range unusedResourceScans(ctx, cfg, start, end, unusedForDays) {
+		resources, err := scan.run()
+		if err != nil {
+			log.Printf("Failed to list %s: %v", scan.name, err)
+			errors = append(errors, err)
+			continue
 		}
+		allResources = append(allResources, resources...)
 	}
 
 	// If no resources and errors occurred, return an error
@@ -126,4 +80,34 @@ func ListUnusedResources(ctx context.Context, cfg *config.Config, start, end tim
 
 	log.Printf("Returning %d unused paid resources", len(allResources))
 	return allResources, nil
-}
\ No newline at end of file
+}
+
+// ListOptimizations fetches in-use resources with a cheaper configuration: modernization
+// findings, S3 storage class changes, Auto Scaling minimum capacity, DynamoDB capacity mode,
+// capacity, unused index and table class changes, Aurora Serverless v2 minimum capacity and
+// SageMaker notebook uptime.
+func ListOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	optimizations := []models.Optimization{}
+	var errors []error
+
+	for _, list := range []func(context.Context, *config.Config) ([]models.Optimization, error){
+		ListModernizationOptimizations,
+		ListS3StorageOptimizations,
+		ListAutoScalingOptimizations,
+		ListDynamoDBOptimizations,
+		ListAuroraOptimizations,
+		ListSageMakerOptimizations,
+	} {
+		found, err := list(ctx, cfg)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		optimizations = append(optimizations, found...)
+	}
+
+	if len(optimizations) == 0 && len(errors) > 0 {
+		return nil, fmt.Errorf("failed to list optimizations: %v", errors[0])
+	}
+	return optimizations, nil
+}
@@ -0,0 +1,501 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/computeoptimizer"
+	cotypes "github.com/aws/aws-sdk-go-v2/service/computeoptimizer/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/deepanshumishra/devcost-api/internal/rightsizing"
+)
+
+// ListRightsizingRecommendations recommends smaller instance types for running EC2 instances and
+// available RDS instances ("ec2", "rds" or "all") from hourly CloudWatch percentiles between start
+// and end. With useComputeOptimizer, each recommendation is cross-checked against Compute Optimizer.
+func ListRightsizingRecommendations(ctx context.Context, cfg *config.Config, start, end time.Time, resourceType string, useComputeOptimizer bool) ([]models.RightsizingRecommendation, error) {
+	recommendations := []models.RightsizingRecommendation{}
+	arns := []string{}
+
+	if resourceType == "all" || resourceType == "ec2" {
+		ec2Recommendations, ec2ARNs, err := listEC2RightsizingRecommendations(ctx, cfg, start, end)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, ec2Recommendations...)
+		arns = append(arns, ec2ARNs...)
+	}
+	if resourceType == "all" || resourceType == "rds" {
+		rdsRecommendations, rdsARNs, err := listRDSRightsizingRecommendations(ctx, cfg, start, end)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rdsRecommendations...)
+		arns = append(arns, rdsARNs...)
+	}
+
+	if useComputeOptimizer && len(recommendations) > 0 {
+		crossCheckComputeOptimizer(ctx, cfg, recommendations, arns)
+	}
+
+	log.Printf("Found %d rightsizing recommendations from %s to %s", len(recommendations), start.Format("2006-01-02"), end.Format("2006-01-02"))
+	return recommendations, nil
+}
+
+// listEC2RightsizingRecommendations sizes running EC2 instances. It returns the recommendations and
+// the matching instance ARNs.
+func listEC2RightsizingRecommendations(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.RightsizingRecommendation, []string, error) {
+	ec2Client := ec2.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	specs := make(map[string]rightsizing.InstanceSpec)
+	loadedFamilies := make(map[string]bool)
+
+	recommendations := []models.RightsizingRecommendation{}
+	arns := []string{}
+
+	paginator := ec2.NewDescribeInstancesPaginator(ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"running"},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe EC2 instances: %v", err)
+			return nil, nil, fmt.Errorf("failed to describe EC2 instances: %v", err)
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceID := aws.ToString(instance.InstanceId)
+				instanceType := string(instance.InstanceType)
+				family, _ := rightsizing.SplitType(instanceType)
+
+				if err := loadInstanceSpecs(ctx, ec2Client, specs, loadedFamilies, rightsizing.CandidateFamilies(family), ""); err != nil {
+					return nil, nil, err
+				}
+				current, ok := specs[instanceType]
+				if !ok {
+					log.Printf("No instance type details for %s (%s), skipping", instanceID, instanceType)
+					continue
+				}
+
+				usage, err := getEC2Utilization(ctx, cwClient, instanceID, start, end)
+				if err != nil {
+					log.Printf("Failed to get utilization for instance %s: %v", instanceID, err)
+					continue
+				}
+
+				platform := aws.ToString(instance.PlatformDetails)
+				price := func(instanceType string) (float64, error) {
+					return getEC2OnDemandPrice(ctx, cfg, region, instanceType, platform)
+				}
+				recommendation, ok := recommendInstance(current, usage, specs, price)
+				if !ok {
+					continue
+				}
+
+				recommendations = append(recommendations, toRightsizingRecommendation("EC2", instanceID, region, recommendation, usage))
+				arns = append(arns, fmt.Sprintf("arn:aws:ec2:%s:%s:instance/%s", region, aws.ToString(reservation.OwnerId), instanceID))
+			}
+		}
+	}
+
+	return recommendations, arns, nil
+}
+
+// listRDSRightsizingRecommendations sizes available RDS instances. Instance class capacities come
+// from their EC2 equivalents and candidates are limited to classes orderable for the engine version.
+func listRDSRightsizingRecommendations(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.RightsizingRecommendation, []string, error) {
+	rdsClient := rds.NewFromConfig(cfg.AWSConfig)
+	ec2Client := ec2.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	specs := make(map[string]rightsizing.InstanceSpec)
+	loadedFamilies := make(map[string]bool)
+	orderable := make(map[string]map[string]bool)
+
+	recommendations := []models.RightsizingRecommendation{}
+	arns := []string{}
+
+	paginator := rds.NewDescribeDBInstancesPaginator(rdsClient, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe RDS instances: %v", err)
+			return nil, nil, fmt.Errorf("failed to describe RDS instances: %v", err)
+		}
+
+		for _, instance := range page.DBInstances {
+			dbInstanceID := aws.ToString(instance.DBInstanceIdentifier)
+			instanceClass := aws.ToString(instance.DBInstanceClass)
+			engine := aws.ToString(instance.Engine)
+			if aws.ToString(instance.DBInstanceStatus) != "available" || instanceClass == "db.serverless" {
+				continue
+			}
+
+			family, _ := rightsizing.SplitType(instanceClass)
+			ec2Families := []string{}
+			for _, candidateFamily := range rightsizing.CandidateFamilies(family) {
+				ec2Families = append(ec2Families, strings.TrimPrefix(candidateFamily, "db."))
+			}
+			if err := loadInstanceSpecs(ctx, ec2Client, specs, loadedFamilies, ec2Families, "db."); err != nil {
+				return nil, nil, err
+			}
+			current, ok := specs[instanceClass]
+			if !ok {
+				log.Printf("No instance class details for %s (%s), skipping", dbInstanceID, instanceClass)
+				continue
+			}
+
+			versionKey := engine + "/" + aws.ToString(instance.EngineVersion)
+			if _, ok := orderable[versionKey]; !ok {
+				classes, err := getOrderableDBInstanceClasses(ctx, rdsClient, engine, aws.ToString(instance.EngineVersion))
+				if err != nil {
+					log.Printf("Failed to get orderable instance classes for %s: %v", versionKey, err)
+					continue
+				}
+				orderable[versionKey] = classes
+			}
+			candidates := make(map[string]rightsizing.InstanceSpec)
+			for class, spec := range specs {
+				if orderable[versionKey][class] {
+					candidates[class] = spec
+				}
+			}
+
+			usage, err := getRDSUtilization(ctx, cwClient, dbInstanceID, current.MemoryGiB, start, end)
+			if err != nil {
+				log.Printf("Failed to get utilization for RDS instance %s: %v", dbInstanceID, err)
+				continue
+			}
+
+			multiAZ := aws.ToBool(instance.MultiAZ)
+			price := func(instanceClass string) (float64, error) {
+				return getRDSOnDemandPrice(ctx, cfg, region, instanceClass, engine, aws.ToString(instance.LicenseModel), multiAZ)
+			}
+			recommendation, ok := recommendInstance(current, usage, candidates, price)
+			if !ok {
+				continue
+			}
+
+			recommendations = append(recommendations, toRightsizingRecommendation("RDS", dbInstanceID, region, recommendation, usage))
+			arns = append(arns, aws.ToString(instance.DBInstanceArn))
+		}
+	}
+
+	return recommendations, arns, nil
+}
+
+// loadInstanceSpecs adds the EC2 instance types of families not yet loaded to specs, keyed by type
+// with the given prefix ("db." for RDS instance classes).
+func loadInstanceSpecs(ctx context.Context, client *ec2.Client, specs map[string]rightsizing.InstanceSpec, loaded map[string]bool, families []string, prefix string) error {
+	patterns := []string{}
+	for _, family := range families {
+		if !loaded[prefix+family] {
+			loaded[prefix+family] = true
+			patterns = append(patterns, family+".*")
+		}
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	paginator := ec2.NewDescribeInstanceTypesPaginator(client, &ec2.DescribeInstanceTypesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: patterns,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe instance types %v: %v", patterns, err)
+			return fmt.Errorf("failed to describe instance types: %v", err)
+		}
+		for _, info := range page.InstanceTypes {
+			spec := rightsizing.InstanceSpec{
+				Type:         prefix + string(info.InstanceType),
+				Architecture: "x86_64",
+			}
+			if info.VCpuInfo != nil {
+				spec.VCPUs = aws.ToInt32(info.VCpuInfo.DefaultVCpus)
+			}
+			if info.MemoryInfo != nil {
+				spec.MemoryGiB = float64(aws.ToInt64(info.MemoryInfo.SizeInMiB)) / 1024
+			}
+			if info.NetworkInfo != nil && len(info.NetworkInfo.NetworkCards) > 0 {
+				spec.NetworkGbps = aws.ToFloat64(info.NetworkInfo.NetworkCards[0].BaselineBandwidthInGbps)
+			}
+			if info.ProcessorInfo != nil {
+				for _, architecture := range info.ProcessorInfo.SupportedArchitectures {
+					if architecture == ec2types.ArchitectureTypeArm64 {
+						spec.Architecture = "arm64"
+					}
+				}
+			}
+			specs[spec.Type] = spec
+		}
+	}
+	return nil
+}
+
+// getOrderableDBInstanceClasses lists the instance classes available for an RDS engine version.
+func getOrderableDBInstanceClasses(ctx context.Context, client *rds.Client, engine, engineVersion string) (map[string]bool, error) {
+	classes := make(map[string]bool)
+	paginator := rds.NewDescribeOrderableDBInstanceOptionsPaginator(client, &rds.DescribeOrderableDBInstanceOptionsInput{
+		Engine:        aws.String(engine),
+		EngineVersion: aws.String(engineVersion),
+		MaxRecords:    aws.Int32(1000),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, option := range page.OrderableDBInstanceOptions {
+			classes[aws.ToString(option.DBInstanceClass)] = true
+		}
+	}
+	return classes, nil
+}
+
+// recommendInstance prices the candidate types that fit the observed load and picks the cheapest.
+// Only types with no more vCPUs than the current one are priced, as larger ones are rarely cheaper.
+func recommendInstance(current rightsizing.InstanceSpec, usage rightsizing.Utilization, specs map[string]rightsizing.InstanceSpec, price func(string) (float64, error)) (rightsizing.Recommendation, bool) {
+	if usage.DataPoints < rightsizing.MinDataPoints {
+		return rightsizing.Recommendation{}, false
+	}
+
+	currentPrice, err := price(current.Type)
+	if err != nil || currentPrice == 0 {
+		log.Printf("No on-demand price for %s, skipping: %v", current.Type, err)
+		return rightsizing.Recommendation{}, false
+	}
+	current.HourlyPrice = currentPrice
+
+	family, _ := rightsizing.SplitType(current.Type)
+	families := make(map[string]bool)
+	for _, candidateFamily := range rightsizing.CandidateFamilies(family) {
+		families[candidateFamily] = true
+	}
+
+	candidates := []rightsizing.InstanceSpec{}
+	for instanceType, spec := range specs {
+		candidateFamily, _ := rightsizing.SplitType(instanceType)
+		if !families[candidateFamily] || instanceType == current.Type || spec.VCPUs > current.VCPUs || !rightsizing.Fits(current, usage, spec) {
+			continue
+		}
+		spec.HourlyPrice, err = price(instanceType)
+		if err != nil {
+			log.Printf("No on-demand price for %s: %v", instanceType, err)
+			continue
+		}
+		candidates = append(candidates, spec)
+	}
+
+	return rightsizing.Recommend(current, usage, candidates)
+}
+
+// getEC2Utilization reads hourly CPU, network and, when the CloudWatch agent publishes it, memory
+// utilization for an EC2 instance.
+func getEC2Utilization(ctx context.Context, client *cloudwatch.Client, instanceID string, start, end time.Time) (rightsizing.Utilization, error) {
+	dimensions := []cwtypes.Dimension{{Name: aws.String("InstanceId"), Value: aws.String(instanceID)}}
+	queries := []cwtypes.MetricDataQuery{
+		metricQuery("cpu", "AWS/EC2", "CPUUtilization", "Average", dimensions, 3600, true),
+		metricQuery("cpu_max", "AWS/EC2", "CPUUtilization", "Maximum", dimensions, 3600, true),
+		metricQuery("net_in", "AWS/EC2", "NetworkIn", "Sum", dimensions, 3600, false),
+		metricQuery("net_out", "AWS/EC2", "NetworkOut", "Sum", dimensions, 3600, false),
+		{
+			Id:         aws.String("network"),
+			Expression: aws.String("(net_in+net_out)*8/3600/1000000000"),
+		},
+	}
+
+	// The agent publishes memory with whatever dimensions it was configured with, so find the series
+	memoryMetrics, err := client.ListMetrics(ctx, &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String("CWAgent"),
+		MetricName: aws.String("mem_used_percent"),
+		Dimensions: []cwtypes.DimensionFilter{{Name: aws.String("InstanceId"), Value: aws.String(instanceID)}},
+	})
+	if err != nil {
+		log.Printf("Failed to list CloudWatch agent memory metrics for instance %s: %v", instanceID, err)
+	} else if len(memoryMetrics.Metrics) > 0 {
+		queries = append(queries, metricQuery("memory", "CWAgent", "mem_used_percent", "Average", memoryMetrics.Metrics[0].Dimensions, 3600, true))
+	}
+
+	values, err := getMetricData(ctx, client, queries, start, end)
+	if err != nil {
+		return rightsizing.Utilization{}, err
+	}
+
+	usage := summariseUtilization(values)
+	if memory, ok := values["memory"]; ok && len(memory) > 0 {
+		usage.MemoryP95 = rightsizing.Percentile(memory, 95)
+		usage.HasMemory = true
+	}
+	return usage, nil
+}
+
+// getRDSUtilization reads hourly CPU, network and memory utilization for an RDS instance. Memory
+// use is derived from FreeableMemory and the instance class's memory.
+func getRDSUtilization(ctx context.Context, client *cloudwatch.Client, dbInstanceID string, memoryGiB float64, start, end time.Time) (rightsizing.Utilization, error) {
+	dimensions := []cwtypes.Dimension{{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(dbInstanceID)}}
+	queries := []cwtypes.MetricDataQuery{
+		metricQuery("cpu", "AWS/RDS", "CPUUtilization", "Average", dimensions, 3600, true),
+		metricQuery("cpu_max", "AWS/RDS", "CPUUtilization", "Maximum", dimensions, 3600, true),
+		metricQuery("net_rx", "AWS/RDS", "NetworkReceiveThroughput", "Average", dimensions, 3600, false),
+		metricQuery("net_tx", "AWS/RDS", "NetworkTransmitThroughput", "Average", dimensions, 3600, false),
+		{
+			Id:         aws.String("network"),
+			Expression: aws.String("(net_rx+net_tx)*8/1000000000"),
+		},
+		metricQuery("free_memory", "AWS/RDS", "FreeableMemory", "Minimum", dimensions, 3600, true),
+	}
+
+	values, err := getMetricData(ctx, client, queries, start, end)
+	if err != nil {
+		return rightsizing.Utilization{}, err
+	}
+
+	usage := summariseUtilization(values)
+	if freeMemory := values["free_memory"]; len(freeMemory) > 0 && memoryGiB > 0 {
+		used := make([]float64, 0, len(freeMemory))
+		for _, free := range freeMemory {
+			used = append(used, math.Max(0, 100*(1-free/(memoryGiB*1024*1024*1024))))
+		}
+		usage.MemoryP95 = rightsizing.Percentile(used, 95)
+		usage.HasMemory = true
+	}
+	return usage, nil
+}
+
+// summariseUtilization computes CPU and network percentiles from the "cpu", "cpu_max" and
+// "network" series.
+func summariseUtilization(values map[string][]float64) rightsizing.Utilization {
+	usage := rightsizing.Utilization{
+		CPUP95:         rightsizing.Percentile(values["cpu"], 95),
+		NetworkP95Gbps: rightsizing.Percentile(values["network"], 95),
+		DataPoints:     len(values["cpu"]),
+	}
+	for _, value := range values["cpu_max"] {
+		usage.CPUMax = math.Max(usage.CPUMax, value)
+	}
+	return usage
+}
+
+// toRightsizingRecommendation converts a sizing result to the API model.
+func toRightsizingRecommendation(resourceType, resourceID, region string, recommendation rightsizing.Recommendation, usage rightsizing.Utilization) models.RightsizingRecommendation {
+	current := recommendation.Current
+	result := models.RightsizingRecommendation{
+		ResourceType:            resourceType,
+		ResourceID:              resourceID,
+		Region:                  region,
+		CurrentType:             current.Type,
+		RecommendedType:         recommendation.Target.Type,
+		CPUP95:                  usage.CPUP95,
+		CPUMax:                  usage.CPUMax,
+		NetworkP95Mbps:          usage.NetworkP95Gbps * 1000,
+		CurrentHourlyPrice:      current.HourlyPrice,
+		RecommendedHourlyPrice:  recommendation.Target.HourlyPrice,
+		EstimatedMonthlySavings: (current.HourlyPrice - recommendation.Target.HourlyPrice) * rightsizing.HoursPerMonth,
+		Currency:                "USD",
+		Confidence:              recommendation.Confidence,
+		Reason:                  recommendation.Reason,
+	}
+	if usage.HasMemory {
+		memoryP95 := usage.MemoryP95
+		result.MemoryP95 = &memoryP95
+	}
+	return result
+}
+
+// crossCheckComputeOptimizer attaches Compute Optimizer's finding for each recommendation, matched
+// by ARN (arns[i] belongs to recommendations[i]). Compute Optimizer errors, e.g. when the account
+// is not opted in, are logged and the recommendations are left as they are.
+func crossCheckComputeOptimizer(ctx context.Context, cfg *config.Config, recommendations []models.RightsizingRecommendation, arns []string) {
+	client := computeoptimizer.NewFromConfig(cfg.AWSConfig)
+	checks := make(map[string]models.ComputeOptimizerCheck)
+
+	ec2ARNs, rdsARNs := []string{}, []string{}
+	for i, arn := range arns {
+		if recommendations[i].ResourceType == "EC2" {
+			ec2ARNs = append(ec2ARNs, arn)
+		} else {
+			rdsARNs = append(rdsARNs, arn)
+		}
+	}
+
+	if len(ec2ARNs) > 0 {
+		input := &computeoptimizer.GetEC2InstanceRecommendationsInput{InstanceArns: ec2ARNs}
+		for {
+			result, err := client.GetEC2InstanceRecommendations(ctx, input)
+			if err != nil {
+				log.Printf("Failed to get Compute Optimizer EC2 recommendations: %v", err)
+				break
+			}
+			for _, recommendation := range result.InstanceRecommendations {
+				check := models.ComputeOptimizerCheck{Finding: string(recommendation.Finding)}
+				for _, option := range recommendation.RecommendationOptions {
+					if option.Rank == 1 {
+						check.RecommendedType = aws.ToString(option.InstanceType)
+					}
+				}
+				check.Agrees = recommendation.Finding == cotypes.FindingOverProvisioned
+				checks[aws.ToString(recommendation.InstanceArn)] = check
+			}
+			if result.NextToken == nil {
+				break
+			}
+			input.NextToken = result.NextToken
+		}
+	}
+
+	if len(rdsARNs) > 0 {
+		input := &computeoptimizer.GetRDSDatabaseRecommendationsInput{ResourceArns: rdsARNs}
+		for {
+			result, err := client.GetRDSDatabaseRecommendations(ctx, input)
+			if err != nil {
+				log.Printf("Failed to get Compute Optimizer RDS recommendations: %v", err)
+				break
+			}
+			for _, recommendation := range result.RdsDBRecommendations {
+				check := models.ComputeOptimizerCheck{Finding: string(recommendation.InstanceFinding)}
+				for _, option := range recommendation.InstanceRecommendationOptions {
+					if option.Rank == 1 {
+						check.RecommendedType = aws.ToString(option.DbInstanceClass)
+					}
+				}
+				check.Agrees = recommendation.InstanceFinding == cotypes.RDSInstanceFindingOverProvisioned
+				checks[aws.ToString(recommendation.ResourceArn)] = check
+			}
+			if result.NextToken == nil {
+				break
+			}
+			input.NextToken = result.NextToken
+		}
+	}
+
+	for i, arn := range arns {
+		if check, ok := checks[arn]; ok {
+			recommendations[i].ComputeOptimizer = &check
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ListUnusedHostedZones identifies Route 53 hosted zones with no records beyond the default NS
+// and SOA, and public zones that answered no DNS queries between start and end.
+func ListUnusedHostedZones(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := route53.NewFromConfig(cfg.AWSConfig)
+	// Route 53 publishes query metrics in us-east-1 only
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig, func(o *cloudwatch.Options) { o.Region = "us-east-1" })
+	unusedZones := []models.UnusedResource{}
+
+	paginator := route53.NewListHostedZonesPaginator(client, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list Route 53 hosted zones: %v", err)
+			return nil, fmt.Errorf("failed to list Route 53 hosted zones: %v", err)
+		}
+
+		for _, zone := range page.HostedZones {
+			zoneID := strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/")
+			zoneName := aws.ToString(zone.Name)
+			// Zones managed by another service, e.g. Cloud Map namespaces, are deleted with it
+			if zone.LinkedService != nil {
+				continue
+			}
+
+			reason := ""
+			if aws.ToInt64(zone.ResourceRecordSetCount) <= 2 {
+				reason = "No records besides NS and SOA"
+			} else if zone.Config == nil || !zone.Config.PrivateZone {
+				dimensions := []cwtypes.Dimension{{Name: aws.String("HostedZoneId"), Value: aws.String(zoneID)}}
+				values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+					metricQuery("queries", "AWS/Route53", "DNSQueries", "Sum", dimensions, 86400, true),
+				}, start, end)
+				if err != nil {
+					log.Printf("Failed to get metrics for Route 53 hosted zone %s: %v", zoneID, err)
+					continue
+				}
+				if sumValues(values["queries"]) > 0 {
+					continue
+				}
+				reason = fmt.Sprintf("No DNS queries for %d days", int(end.Sub(start).Hours()/24))
+			} else {
+				continue
+			}
+
+			unusedZones = append(unusedZones, models.UnusedResource{
+				ResourceType:         "route53:hostedzone",
+				ResourceID:           zoneID,
+				Reason:               reason + " (" + zoneName + ")",
+				EstimatedMonthlyCost: route53HostedZoneMonthlyPrice,
+			})
+			log.Printf("Found unused Route 53 hosted zone: %s", zoneID)
+		}
+	}
+
+	return unusedZones, nil
+}
@@ -0,0 +1,413 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+const (
+	// Buckets storing less than this are not reported for missing lifecycle rules.
+	s3LifecycleMinBytes = bytesPerGB
+	// Buckets with less Standard storage than this are not Intelligent-Tiering candidates.
+	s3IntelligentTieringMinBytes = 100 * bytesPerGB
+	// Share of Standard data assumed not accessed for 30 days, and so moved to the Infrequent
+	// Access tier by Intelligent-Tiering. Without request metrics the real share is unknown.
+	s3InfrequentAccessShare = 0.5
+	// Object versions listed and multipart uploads sized per bucket, to bound API calls.
+	s3VersionScanLimit   = 10000
+	s3MultipartScanLimit = 100
+)
+
+// s3Bucket is a bucket with the region its metrics and API calls must use.
+type s3Bucket struct {
+	name    string
+	region  string
+	created time.Time
+}
+
+// s3BucketStorage is a bucket's latest daily storage metrics.
+type s3BucketStorage struct {
+	bytesByStorageType map[string]float64
+	objects            float64
+}
+
+// totalBytes is the bucket's size across all storage classes.
+func (s s3BucketStorage) totalBytes() float64 {
+	total := 0.0
+	for _, bytes := range s.bytesByStorageType {
+		total += bytes
+	}
+	return total
+}
+
+// ListUnusedS3Buckets identifies empty buckets, buckets over 1 GiB with no lifecycle rules,
+// incomplete multipart uploads started before start, and noncurrent object versions that no
+// lifecycle rule expires.
+func ListUnusedS3Buckets(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	buckets, err := listS3Buckets(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	unusedBuckets := []models.UnusedResource{}
+
+	for region, regionBuckets := range groupS3BucketsByRegion(buckets) {
+		client := s3.NewFromConfig(cfg.AWSConfig, func(o *s3.Options) { o.Region = region })
+		storage, err := getS3StorageMetrics(ctx, cfg, region, end)
+		if err != nil {
+			log.Printf("Failed to get S3 storage metrics in %s: %v", region, err)
+			continue
+		}
+
+		for _, bucket := range regionBuckets {
+			bucketStorage, hasMetrics := storage[bucket.name]
+			// Storage metrics are published daily, so only buckets older than the metrics lookback
+			// are known to be empty
+			if !hasMetrics || bucketStorage.objects == 0 {
+				if bucket.created.Before(end.AddDate(0, 0, -3)) {
+					unusedBuckets = append(unusedBuckets, models.UnusedResource{
+						ResourceType: "s3:bucket",
+						ResourceID:   bucket.name,
+						Reason:       "Empty bucket",
+					})
+					log.Printf("Found empty S3 bucket: %s", bucket.name)
+				}
+				continue
+			}
+
+			rules, err := getS3LifecycleRules(ctx, client, bucket.name)
+			if err != nil {
+				log.Printf("Failed to get lifecycle configuration for S3 bucket %s: %v", bucket.name, err)
+				continue
+			}
+			if len(rules) == 0 && bucketStorage.totalBytes() >= s3LifecycleMinBytes {
+				unusedBuckets = append(unusedBuckets, models.UnusedResource{
+					ResourceType: "s3:bucket",
+					ResourceID:   bucket.name,
+					Reason:       fmt.Sprintf("No lifecycle rules for %.1f GiB in %.0f objects", bucketStorage.totalBytes()/bytesPerGB, bucketStorage.objects),
+				})
+				log.Printf("Found S3 bucket without lifecycle rules: %s", bucket.name)
+			}
+
+			if finding, ok := s3IncompleteMultipartUploads(ctx, client, bucket.name, rules, start); ok {
+				unusedBuckets = append(unusedBuckets, finding)
+			}
+			if finding, ok := s3NoncurrentVersions(ctx, client, bucket.name, rules); ok {
+				unusedBuckets = append(unusedBuckets, finding)
+			}
+		}
+	}
+
+	return unusedBuckets, nil
+}
+
+// ListS3StorageOptimizations finds buckets with over 100 GiB in S3 Standard and no lifecycle
+// transitions, and estimates the savings from Intelligent-Tiering moving the assumed
+// s3InfrequentAccessShare to the Infrequent Access tier, net of the monitoring fee.
+func ListS3StorageOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	buckets, err := listS3Buckets(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	optimizations := []models.Optimization{}
+
+	for region, regionBuckets := range groupS3BucketsByRegion(buckets) {
+		client := s3.NewFromConfig(cfg.AWSConfig, func(o *s3.Options) { o.Region = region })
+		storage, err := getS3StorageMetrics(ctx, cfg, region, time.Now())
+		if err != nil {
+			log.Printf("Failed to get S3 storage metrics in %s: %v", region, err)
+			continue
+		}
+
+		for _, bucket := range regionBuckets {
+			bucketStorage := storage[bucket.name]
+			standardBytes := bucketStorage.bytesByStorageType["StandardStorage"]
+			if standardBytes < s3IntelligentTieringMinBytes {
+				continue
+			}
+
+			rules, err := getS3LifecycleRules(ctx, client, bucket.name)
+			if err != nil {
+				log.Printf("Failed to get lifecycle configuration for S3 bucket %s: %v", bucket.name, err)
+				continue
+			}
+			if s3HasTransitions(rules) {
+				continue
+			}
+
+			standardGB := standardBytes / bytesPerGB
+			savings := standardGB*s3InfrequentAccessShare*(s3StandardPerGBMonthPrice-s3InfrequentAccessPerGBMonthPrice) -
+				bucketStorage.objects/1000*s3IntelligentTieringMonitoringPer1000Objects
+			if savings <= 0 {
+				continue
+			}
+			optimizations = append(optimizations, models.Optimization{
+				Category:                 "storage-class",
+				ResourceType:             "s3:bucket",
+				ResourceID:               bucket.name,
+				CurrentConfiguration:     fmt.Sprintf("S3 Standard, %.0f GiB", standardGB),
+				RecommendedConfiguration: "S3 Intelligent-Tiering",
+				Reason:                   fmt.Sprintf("No lifecycle transitions; savings assume %.0f%% of data is not accessed for 30 days", s3InfrequentAccessShare*100),
+				EstimatedMonthlySavings:  savings,
+			})
+		}
+	}
+
+	log.Printf("Found %d S3 storage optimizations", len(optimizations))
+	return optimizations, nil
+}
+
+// listS3Buckets lists the account's buckets with their regions.
+func listS3Buckets(ctx context.Context, cfg *config.Config) ([]s3Bucket, error) {
+	client := s3.NewFromConfig(cfg.AWSConfig)
+	buckets := []s3Bucket{}
+
+	paginator := s3.NewListBucketsPaginator(client, &s3.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list S3 buckets: %v", err)
+			return nil, fmt.Errorf("failed to list S3 buckets: %v", err)
+		}
+
+		for _, bucket := range page.Buckets {
+			region := aws.ToString(bucket.BucketRegion)
+			if region == "" {
+				location, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: bucket.Name})
+				if err != nil {
+					log.Printf("Failed to get location of S3 bucket %s: %v", aws.ToString(bucket.Name), err)
+					continue
+				}
+				// Buckets in us-east-1 have an empty location constraint
+				region = string(location.LocationConstraint)
+				if region == "" {
+					region = "us-east-1"
+				}
+			}
+			buckets = append(buckets, s3Bucket{name: aws.ToString(bucket.Name), region: region, created: aws.ToTime(bucket.CreationDate)})
+		}
+	}
+
+	return buckets, nil
+}
+
+// groupS3BucketsByRegion groups buckets by region.
+func groupS3BucketsByRegion(buckets []s3Bucket) map[string][]s3Bucket {
+	byRegion := make(map[string][]s3Bucket)
+	for _, bucket := range buckets {
+		byRegion[bucket.region] = append(byRegion[bucket.region], bucket)
+	}
+	return byRegion
+}
+
+// getS3StorageMetrics returns the latest BucketSizeBytes per storage type and NumberOfObjects for
+// every bucket with storage metrics in a region, over the three days before end.
+func getS3StorageMetrics(ctx context.Context, cfg *config.Config, region string, end time.Time) (map[string]s3BucketStorage, error) {
+	client := cloudwatch.NewFromConfig(cfg.AWSConfig, func(o *cloudwatch.Options) { o.Region = region })
+	storage := make(map[string]s3BucketStorage)
+
+	metrics := []cwtypes.Metric{}
+	for _, metricName := range []string{"BucketSizeBytes", "NumberOfObjects"} {
+		paginator := cloudwatch.NewListMetricsPaginator(client, &cloudwatch.ListMetricsInput{
+			Namespace:  aws.String("AWS/S3"),
+			MetricName: aws.String(metricName),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, page.Metrics...)
+		}
+	}
+
+	// GetMetricData accepts at most 500 queries per request
+	for batchStart := 0; batchStart < len(metrics); batchStart += 500 {
+		batch := metrics[batchStart:min(batchStart+500, len(metrics))]
+		queries := make([]cwtypes.MetricDataQuery, 0, len(batch))
+		for i, metric := range batch {
+			queries = append(queries, metricQuery(fmt.Sprintf("m%d", i), "AWS/S3", aws.ToString(metric.MetricName), "Average", metric.Dimensions, 86400, true))
+		}
+		values, err := getMetricData(ctx, client, queries, end.AddDate(0, 0, -3), end)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, metric := range batch {
+			series := values[fmt.Sprintf("m%d", i)]
+			if len(series) == 0 {
+				continue
+			}
+			bucketName, storageType := "", ""
+			for _, dimension := range metric.Dimensions {
+				switch aws.ToString(dimension.Name) {
+				case "BucketName":
+					bucketName = aws.ToString(dimension.Value)
+				case "StorageType":
+					storageType = aws.ToString(dimension.Value)
+				}
+			}
+
+			bucketStorage, ok := storage[bucketName]
+			if !ok {
+				bucketStorage = s3BucketStorage{bytesByStorageType: make(map[string]float64)}
+			}
+			// Results are newest first
+			if aws.ToString(metric.MetricName) == "NumberOfObjects" {
+				bucketStorage.objects = series[0]
+			} else {
+				bucketStorage.bytesByStorageType[storageType] = series[0]
+			}
+			storage[bucketName] = bucketStorage
+		}
+	}
+
+	return storage, nil
+}
+
+// getS3LifecycleRules returns a bucket's enabled lifecycle rules, or none when it has no
+// lifecycle configuration.
+func getS3LifecycleRules(ctx context.Context, client *s3.Client, bucketName string) ([]types.LifecycleRule, error) {
+	result, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rules := []types.LifecycleRule{}
+	for _, rule := range result.Rules {
+		if rule.Status == types.ExpirationStatusEnabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// s3HasTransitions reports whether any lifecycle rule moves current objects to another storage
+// class.
+func s3HasTransitions(rules []types.LifecycleRule) bool {
+	for _, rule := range rules {
+		if len(rule.Transitions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// s3IncompleteMultipartUploads reports multipart uploads started before start in a bucket with no
+// rule aborting them. Parts of up to s3MultipartScanLimit uploads are sized for the estimate.
+func s3IncompleteMultipartUploads(ctx context.Context, client *s3.Client, bucketName string, rules []types.LifecycleRule, start time.Time) (models.UnusedResource, bool) {
+	for _, rule := range rules {
+		if rule.AbortIncompleteMultipartUpload != nil {
+			return models.UnusedResource{}, false
+		}
+	}
+
+	uploads, sized, bytes := 0, 0, 0.0
+	paginator := s3.NewListMultipartUploadsPaginator(client, &s3.ListMultipartUploadsInput{Bucket: aws.String(bucketName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list multipart uploads for S3 bucket %s: %v", bucketName, err)
+			return models.UnusedResource{}, false
+		}
+
+		for _, upload := range page.Uploads {
+			if upload.Initiated == nil || upload.Initiated.After(start) {
+				continue
+			}
+			uploads++
+			if sized >= s3MultipartScanLimit {
+				continue
+			}
+			sized++
+			partsPaginator := s3.NewListPartsPaginator(client, &s3.ListPartsInput{Bucket: aws.String(bucketName), Key: upload.Key, UploadId: upload.UploadId})
+			for partsPaginator.HasMorePages() {
+				parts, err := partsPaginator.NextPage(ctx)
+				if err != nil {
+					log.Printf("Failed to list parts of multipart upload %s in S3 bucket %s: %v", aws.ToString(upload.UploadId), bucketName, err)
+					break
+				}
+				for _, part := range parts.Parts {
+					bytes += float64(aws.ToInt64(part.Size))
+				}
+			}
+		}
+	}
+	if uploads == 0 {
+		return models.UnusedResource{}, false
+	}
+
+	log.Printf("Found incomplete multipart uploads in S3 bucket: %s", bucketName)
+	return models.UnusedResource{
+		ResourceType:         "s3:bucket",
+		ResourceID:           bucketName,
+		Reason:               fmt.Sprintf("%d incomplete multipart uploads started before %s (%.1f GiB in %d sized) and no rule to abort them", uploads, start.Format("2006-01-02"), bytes/bytesPerGB, sized),
+		EstimatedMonthlyCost: bytes / bytesPerGB * s3StandardPerGBMonthPrice,
+	}, true
+}
+
+// s3NoncurrentVersions reports noncurrent object versions in a versioned bucket with no rule
+// expiring or transitioning them. Only the first s3VersionScanLimit versions are sized.
+func s3NoncurrentVersions(ctx context.Context, client *s3.Client, bucketName string, rules []types.LifecycleRule) (models.UnusedResource, bool) {
+	for _, rule := range rules {
+		if rule.NoncurrentVersionExpiration != nil || len(rule.NoncurrentVersionTransitions) > 0 {
+			return models.UnusedResource{}, false
+		}
+	}
+	versioning, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		log.Printf("Failed to get versioning of S3 bucket %s: %v", bucketName, err)
+		return models.UnusedResource{}, false
+	}
+	// Suspended buckets keep the versions created while versioning was enabled
+	if versioning.Status == "" {
+		return models.UnusedResource{}, false
+	}
+
+	scanned, noncurrent, bytes := 0, 0, 0.0
+	paginator := s3.NewListObjectVersionsPaginator(client, &s3.ListObjectVersionsInput{Bucket: aws.String(bucketName)})
+	for paginator.HasMorePages() && scanned < s3VersionScanLimit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list object versions for S3 bucket %s: %v", bucketName, err)
+			return models.UnusedResource{}, false
+		}
+		for _, version := range page.Versions {
+			scanned++
+			if !aws.ToBool(version.IsLatest) {
+				noncurrent++
+				bytes += float64(aws.ToInt64(version.Size))
+			}
+		}
+	}
+	if noncurrent == 0 {
+		return models.UnusedResource{}, false
+	}
+
+	reason := fmt.Sprintf("%d noncurrent versions (%.1f GiB) and no rule to expire them", noncurrent, bytes/bytesPerGB)
+	if paginator.HasMorePages() {
+		reason = fmt.Sprintf("At least %d noncurrent versions (%.1f GiB in the first %d versions) and no rule to expire them", noncurrent, bytes/bytesPerGB, scanned)
+	}
+	log.Printf("Found unexpired noncurrent versions in S3 bucket: %s", bucketName)
+	return models.UnusedResource{
+		ResourceType:         "s3:bucket",
+		ResourceID:           bucketName,
+		Reason:               reason,
+		EstimatedMonthlyCost: bytes / bytesPerGB * s3StandardPerGBMonthPrice,
+	}, true
+}
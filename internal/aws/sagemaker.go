@@ -0,0 +1,329 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+	"github.com/deepanshumishra/devcost-api/internal/rightsizing"
+)
+
+// ListUnusedSageMakerResources identifies real-time endpoints with no invocations, notebook
+// instances with no Jupyter activity for sageMakerNotebookIdleDays, and Studio apps with no user
+// activity since start.
+func ListUnusedSageMakerResources(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := sagemaker.NewFromConfig(cfg.AWSConfig)
+	unusedResources := []models.UnusedResource{}
+
+	endpoints, err := listIdleSageMakerEndpoints(ctx, cfg, client, start, end)
+	if err != nil {
+		return nil, err
+	}
+	unusedResources = append(unusedResources, endpoints...)
+
+	notebooks, err := listIdleSageMakerNotebooks(ctx, cfg, client, sageMakerNotebookIdleSince())
+	if err != nil {
+		return nil, err
+	}
+	unusedResources = append(unusedResources, notebooks...)
+
+	apps, err := listIdleSageMakerStudioApps(ctx, cfg, client, start)
+	if err != nil {
+		return nil, err
+	}
+	unusedResources = append(unusedResources, apps...)
+
+	return unusedResources, nil
+}
+
+// listIdleSageMakerEndpoints finds in-service endpoints whose instance-backed variants had no
+// invocations between start and end. Serverless variants cost nothing while idle.
+func listIdleSageMakerEndpoints(ctx context.Context, cfg *config.Config, client *sagemaker.Client, start, end time.Time) ([]models.UnusedResource, error) {
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	idleEndpoints := []models.UnusedResource{}
+
+	paginator := sagemaker.NewListEndpointsPaginator(client, &sagemaker.ListEndpointsInput{
+		StatusEquals: types.EndpointStatusInService,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list SageMaker endpoints: %v", err)
+			return nil, fmt.Errorf("failed to list SageMaker endpoints: %v", err)
+		}
+
+		for _, summary := range page.Endpoints {
+			endpointName := aws.ToString(summary.EndpointName)
+			endpoint, err := client.DescribeEndpoint(ctx, &sagemaker.DescribeEndpointInput{EndpointName: summary.EndpointName})
+			if err != nil {
+				log.Printf("Failed to describe SageMaker endpoint %s: %v", endpointName, err)
+				continue
+			}
+			endpointConfig, err := client.DescribeEndpointConfig(ctx, &sagemaker.DescribeEndpointConfigInput{EndpointConfigName: endpoint.EndpointConfigName})
+			if err != nil {
+				log.Printf("Failed to describe SageMaker endpoint config for %s: %v", endpointName, err)
+				continue
+			}
+			instanceTypes := make(map[string]string)
+			for _, variant := range endpointConfig.ProductionVariants {
+				instanceTypes[aws.ToString(variant.VariantName)] = string(variant.InstanceType)
+			}
+
+			queries := []cwtypes.MetricDataQuery{}
+			monthlyCost := 0.0
+			instances := int32(0)
+			for _, variant := range endpoint.ProductionVariants {
+				variantName := aws.ToString(variant.VariantName)
+				count := aws.ToInt32(variant.CurrentInstanceCount)
+				if instanceTypes[variantName] == "" || count == 0 {
+					continue
+				}
+				dimensions := []cwtypes.Dimension{
+					{Name: aws.String("EndpointName"), Value: aws.String(endpointName)},
+					{Name: aws.String("VariantName"), Value: aws.String(variantName)},
+				}
+				queries = append(queries, metricQuery(fmt.Sprintf("invocations%d", len(queries)), "AWS/SageMaker", "Invocations", "Sum", dimensions, 86400, true))
+				instances += count
+				monthlyCost += getSageMakerInstancePrice(ctx, cfg, region, instanceTypes[variantName], "Hosting") * float64(count) * hoursPerMonth
+			}
+			if len(queries) == 0 {
+				continue
+			}
+
+			values, err := getMetricData(ctx, cwClient, queries, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for SageMaker endpoint %s: %v", endpointName, err)
+				continue
+			}
+			invocations := 0.0
+			for _, series := range values {
+				invocations += sumValues(series)
+			}
+			if invocations > 0 {
+				continue
+			}
+
+			idleEndpoints = append(idleEndpoints, models.UnusedResource{
+				ResourceType:         "sagemaker:endpoint",
+				ResourceID:           endpointName,
+				Reason:               fmt.Sprintf("No invocations for %d days on %d instances", int(end.Sub(start).Hours()/24), instances),
+				EstimatedMonthlyCost: monthlyCost,
+			})
+			log.Printf("Found idle SageMaker endpoint: %s", endpointName)
+		}
+	}
+
+	return idleEndpoints, nil
+}
+
+// sageMakerNotebookLogGroup holds the Jupyter server logs of notebook instances, one
+// "<name>/jupyter.log" stream per instance.
+const sageMakerNotebookLogGroup = "/aws/sagemaker/NotebookInstances"
+
+// sageMakerNotebookIdleDays is how long a notebook instance's Jupyter server must log nothing for
+// it to be unused. It is fixed rather than taken from the request window so that unused notebooks
+// and the uptime findings of ListSageMakerOptimizations always split notebooks the same way.
+const sageMakerNotebookIdleDays = 14
+
+// sageMakerNotebookIdleSince returns the cutoff before which a notebook's last Jupyter activity
+// makes it unused.
+func sageMakerNotebookIdleSince() time.Time {
+	return time.Now().AddDate(0, 0, -sageMakerNotebookIdleDays)
+}
+
+// listIdleSageMakerNotebooks finds notebook instances in service since idleSince whose Jupyter
+// server logged nothing after it. Notebooks without Jupyter logs have no activity signal and
+// are left to ListSageMakerOptimizations.
+func listIdleSageMakerNotebooks(ctx context.Context, cfg *config.Config, client *sagemaker.Client, idleSince time.Time) ([]models.UnusedResource, error) {
+	logsClient := cloudwatchlogs.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	idleNotebooks := []models.UnusedResource{}
+
+	paginator := sagemaker.NewListNotebookInstancesPaginator(client, &sagemaker.ListNotebookInstancesInput{
+		StatusEquals: types.NotebookInstanceStatusInService,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list SageMaker notebook instances: %v", err)
+			return nil, fmt.Errorf("failed to list SageMaker notebook instances: %v", err)
+		}
+
+		for _, notebook := range page.NotebookInstances {
+			notebookName := aws.ToString(notebook.NotebookInstanceName)
+			lastActivity, idle := isSageMakerNotebookIdle(ctx, logsClient, notebook, idleSince)
+			if !idle {
+				continue
+			}
+
+			reason := fmt.Sprintf("No Jupyter activity since %s", lastActivity.Format("2006-01-02"))
+			if notebook.NotebookInstanceLifecycleConfigName == nil {
+				reason += " and no lifecycle configuration to auto-stop it"
+			}
+			idleNotebooks = append(idleNotebooks, models.UnusedResource{
+				ResourceType:         "sagemaker:notebook-instance",
+				ResourceID:           notebookName,
+				Reason:               reason,
+				EstimatedMonthlyCost: getSageMakerInstancePrice(ctx, cfg, region, string(notebook.InstanceType), "Notebook") * hoursPerMonth,
+			})
+			log.Printf("Found idle SageMaker notebook instance: %s", notebookName)
+		}
+	}
+
+	return idleNotebooks, nil
+}
+
+// ListSageMakerOptimizations reports notebook instances in service without a lifecycle
+// configuration to stop them when idle, estimating savings from stopping them outside business
+// hours. Notebooks with no Jupyter activity for sageMakerNotebookIdleDays are reported as unused
+// instead.
+func ListSageMakerOptimizations(ctx context.Context, cfg *config.Config) ([]models.Optimization, error) {
+	client := sagemaker.NewFromConfig(cfg.AWSConfig)
+	logsClient := cloudwatchlogs.NewFromConfig(cfg.AWSConfig)
+	region := cfg.AWSConfig.Region
+	idleSince := sageMakerNotebookIdleSince()
+	optimizations := []models.Optimization{}
+
+	paginator := sagemaker.NewListNotebookInstancesPaginator(client, &sagemaker.ListNotebookInstancesInput{
+		StatusEquals: types.NotebookInstanceStatusInService,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list SageMaker notebook instances: %v", err)
+			return nil, fmt.Errorf("failed to list SageMaker notebook instances: %v", err)
+		}
+
+		for _, notebook := range page.NotebookInstances {
+			if notebook.NotebookInstanceLifecycleConfigName != nil {
+				continue
+			}
+			notebookName := aws.ToString(notebook.NotebookInstanceName)
+			if _, idle := isSageMakerNotebookIdle(ctx, logsClient, notebook, idleSince); idle {
+				continue
+			}
+			price := getSageMakerInstancePrice(ctx, cfg, region, string(notebook.InstanceType), "Notebook")
+			if price == 0 {
+				continue
+			}
+			optimizations = append(optimizations, models.Optimization{
+				Category:                 "uptime",
+				ResourceType:             "sagemaker:notebook-instance",
+				ResourceID:               notebookName,
+				CurrentConfiguration:     fmt.Sprintf("%s, no auto-stop", notebook.InstanceType),
+				RecommendedConfiguration: "lifecycle configuration that stops the instance when idle",
+				Reason:                   "Runs until stopped by hand, including nights and weekends",
+				EstimatedMonthlySavings:  price * hoursPerMonth * rightsizing.OffHoursShare,
+			})
+		}
+	}
+
+	log.Printf("Found %d SageMaker notebook uptime optimizations", len(optimizations))
+	return optimizations, nil
+}
+
+// isSageMakerNotebookIdle reports whether a notebook instance was in service since idleSince with
+// no Jupyter activity after it, and returns its last activity.
+func isSageMakerNotebookIdle(ctx context.Context, client *cloudwatchlogs.Client, notebook types.NotebookInstanceSummary, idleSince time.Time) (time.Time, bool) {
+	if notebook.LastModifiedTime == nil || notebook.LastModifiedTime.After(idleSince) {
+		return time.Time{}, false
+	}
+	lastActivity, ok := getNotebookLastActivity(ctx, client, aws.ToString(notebook.NotebookInstanceName))
+	return lastActivity, ok && !lastActivity.After(idleSince)
+}
+
+// getNotebookLastActivity returns when a notebook instance's Jupyter server last logged, and
+// false when it has no Jupyter logs.
+func getNotebookLastActivity(ctx context.Context, client *cloudwatchlogs.Client, notebookName string) (time.Time, bool) {
+	result, err := client.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName:        aws.String(sageMakerNotebookLogGroup),
+		LogStreamNamePrefix: aws.String(notebookName + "/jupyter.log"),
+	})
+	if err != nil {
+		log.Printf("Failed to describe Jupyter logs for SageMaker notebook instance %s: %v", notebookName, err)
+		return time.Time{}, false
+	}
+	for _, stream := range result.LogStreams {
+		if aws.ToString(stream.LogStreamName) == notebookName+"/jupyter.log" && stream.LastEventTimestamp != nil {
+			return time.UnixMilli(aws.ToInt64(stream.LastEventTimestamp)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// listIdleSageMakerStudioApps finds in-service Studio apps on a billed instance type whose last
+// user activity was before start. Studio apps are estimated at notebook instance rates.
+func listIdleSageMakerStudioApps(ctx context.Context, cfg *config.Config, client *sagemaker.Client, start time.Time) ([]models.UnusedResource, error) {
+	region := cfg.AWSConfig.Region
+	idleApps := []models.UnusedResource{}
+
+	paginator := sagemaker.NewListAppsPaginator(client, &sagemaker.ListAppsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to list SageMaker Studio apps: %v", err)
+			return nil, fmt.Errorf("failed to list SageMaker Studio apps: %v", err)
+		}
+
+		for _, summary := range page.Apps {
+			if summary.Status != types.AppStatusInService || summary.ResourceSpec == nil || summary.ResourceSpec.InstanceType == "" || summary.ResourceSpec.InstanceType == types.AppInstanceTypeSystem {
+				continue
+			}
+
+			app, err := client.DescribeApp(ctx, &sagemaker.DescribeAppInput{
+				DomainId:        summary.DomainId,
+				AppName:         summary.AppName,
+				AppType:         summary.AppType,
+				UserProfileName: summary.UserProfileName,
+				SpaceName:       summary.SpaceName,
+			})
+			if err != nil {
+				log.Printf("Failed to describe SageMaker Studio app %s: %v", aws.ToString(summary.AppName), err)
+				continue
+			}
+			lastActivity := app.LastUserActivityTimestamp
+			if lastActivity == nil {
+				lastActivity = app.CreationTime
+			}
+			if lastActivity == nil || lastActivity.After(start) {
+				continue
+			}
+
+			appID := aws.ToString(summary.DomainId) + "/" + string(summary.AppType) + "/" + aws.ToString(summary.AppName)
+			idleApps = append(idleApps, models.UnusedResource{
+				ResourceType:         "sagemaker:studio-app",
+				ResourceID:           appID,
+				Reason:               fmt.Sprintf("Running on %s with no user activity since %s", summary.ResourceSpec.InstanceType, lastActivity.Format("2006-01-02")),
+				EstimatedMonthlyCost: getSageMakerInstancePrice(ctx, cfg, region, string(summary.ResourceSpec.InstanceType), "Notebook") * hoursPerMonth,
+			})
+			log.Printf("Found idle SageMaker Studio app: %s", appID)
+		}
+	}
+
+	return idleApps, nil
+}
+
+// getSageMakerInstancePrice returns the hourly on-demand price of an ML instance type for a
+// SageMaker component such as "Hosting" or "Notebook", or 0 when it is unknown.
+func getSageMakerInstancePrice(ctx context.Context, cfg *config.Config, region, instanceType, component string) float64 {
+	price, err := getOnDemandPrice(ctx, cfg, "AmazonSageMaker", map[string]string{
+		"regionCode":   region,
+		"instanceName": instanceType,
+		"component":    component,
+	})
+	if err != nil {
+		log.Printf("No on-demand price for SageMaker %s %s: %v", component, instanceType, err)
+		return 0
+	}
+	return price
+}
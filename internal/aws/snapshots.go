@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ListUnusedSnapshotsAndAMIs identifies EBS snapshots whose source volume is gone and no AMI uses,
+// snapshots older than retentionDays, and AMIs older than retentionDays that no instance or launch
+// template uses. Costs assume the full volume size is stored, an upper bound for incremental snapshots.
+func ListUnusedSnapshotsAndAMIs(ctx context.Context, cfg *config.Config, retentionDays int) ([]models.UnusedResource, error) {
+	client := ec2.NewFromConfig(cfg.AWSConfig)
+	unusedResources := []models.UnusedResource{}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	images, err := describeOwnImages(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	imageSnapshots := make(map[string]bool)
+	for _, image := range images {
+		for _, mapping := range image.BlockDeviceMappings {
+			if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+				imageSnapshots[aws.ToString(mapping.Ebs.SnapshotId)] = true
+			}
+		}
+	}
+
+	volumes := make(map[string]bool)
+	volumePaginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{})
+	for volumePaginator.HasMorePages() {
+		page, err := volumePaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe EBS volumes: %v", err)
+			return nil, fmt.Errorf("failed to describe EBS volumes: %v", err)
+		}
+		for _, volume := range page.Volumes {
+			volumes[aws.ToString(volume.VolumeId)] = true
+		}
+	}
+
+	// Check snapshots; those backing an AMI are judged with the AMI
+	snapshotSizes := make(map[string]int32)
+	snapshotPaginator := ec2.NewDescribeSnapshotsPaginator(client, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+	})
+	for snapshotPaginator.HasMorePages() {
+		page, err := snapshotPaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe EBS snapshots: %v", err)
+			return nil, fmt.Errorf("failed to describe EBS snapshots: %v", err)
+		}
+
+		for _, snapshot := range page.Snapshots {
+			snapshotID := aws.ToString(snapshot.SnapshotId)
+			snapshotSizes[snapshotID] = aws.ToInt32(snapshot.VolumeSize)
+			if imageSnapshots[snapshotID] {
+				continue
+			}
+
+			reason := ""
+			switch {
+			case !volumes[aws.ToString(snapshot.VolumeId)]:
+				reason = "Source volume deleted and not used by any AMI"
+			case snapshot.StartTime != nil && snapshot.StartTime.Before(cutoff):
+				reason = fmt.Sprintf("Older than %d days", retentionDays)
+			default:
+				continue
+			}
+
+			unusedResources = append(unusedResources, models.UnusedResource{
+				ResourceType:         "ec2:snapshot",
+				ResourceID:           snapshotID,
+				Reason:               reason,
+				EstimatedMonthlyCost: float64(aws.ToInt32(snapshot.VolumeSize)) * ebsSnapshotPerGBMonthPrice,
+			})
+			log.Printf("Found unused EBS snapshot: %s (%s)", snapshotID, reason)
+		}
+	}
+
+	usedImages, err := getUsedImageIDs(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check AMIs
+	for _, image := range images {
+		imageID := aws.ToString(image.ImageId)
+		created, err := time.Parse(time.RFC3339, aws.ToString(image.CreationDate))
+		if usedImages[imageID] || err != nil || created.After(cutoff) {
+			continue
+		}
+
+		var sizeGiB int32
+		for _, mapping := range image.BlockDeviceMappings {
+			if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+				sizeGiB += snapshotSizes[aws.ToString(mapping.Ebs.SnapshotId)]
+			}
+		}
+		unusedResources = append(unusedResources, models.UnusedResource{
+			ResourceType:         "ec2:image",
+			ResourceID:           imageID,
+			Reason:               fmt.Sprintf("Not used by any instance or launch template, created over %d days ago", retentionDays),
+			EstimatedMonthlyCost: float64(sizeGiB) * ebsSnapshotPerGBMonthPrice,
+		})
+		log.Printf("Found unused AMI: %s", imageID)
+	}
+
+	return unusedResources, nil
+}
+
+// describeOwnImages lists the AMIs owned by the account.
+func describeOwnImages(ctx context.Context, client *ec2.Client) ([]types.Image, error) {
+	images := []types.Image{}
+	paginator := ec2.NewDescribeImagesPaginator(client, &ec2.DescribeImagesInput{
+		Owners: []string{"self"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe AMIs: %v", err)
+			return nil, fmt.Errorf("failed to describe AMIs: %v", err)
+		}
+		images = append(images, page.Images...)
+	}
+	return images, nil
+}
+
+// getUsedImageIDs returns the AMIs used by non-terminated instances and by the default or latest
+// version of any launch template.
+func getUsedImageIDs(ctx context.Context, client *ec2.Client) (map[string]bool, error) {
+	used := make(map[string]bool)
+
+	instancePaginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"pending", "running", "stopping", "stopped"},
+			},
+		},
+	})
+	for instancePaginator.HasMorePages() {
+		page, err := instancePaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe EC2 instances: %v", err)
+			return nil, fmt.Errorf("failed to describe EC2 instances: %v", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				used[aws.ToString(instance.ImageId)] = true
+			}
+		}
+	}
+
+	templatePaginator := ec2.NewDescribeLaunchTemplatesPaginator(client, &ec2.DescribeLaunchTemplatesInput{})
+	for templatePaginator.HasMorePages() {
+		page, err := templatePaginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe launch templates: %v", err)
+			return nil, fmt.Errorf("failed to describe launch templates: %v", err)
+		}
+		for _, template := range page.LaunchTemplates {
+			versions, err := client.DescribeLaunchTemplateVersions(ctx, &ec2.DescribeLaunchTemplateVersionsInput{
+				LaunchTemplateId: template.LaunchTemplateId,
+				Versions:         []string{"$Default", "$Latest"},
+			})
+			if err != nil {
+				log.Printf("Failed to describe versions of launch template %s: %v", aws.ToString(template.LaunchTemplateId), err)
+				continue
+			}
+			for _, version := range versions.LaunchTemplateVersions {
+				if version.LaunchTemplateData == nil {
+					continue
+				}
+				// Templates resolving the AMI from SSM at launch can't be matched to an image ID
+				if imageID := aws.ToString(version.LaunchTemplateData.ImageId); !strings.HasPrefix(imageID, "resolve:ssm:") {
+					used[imageID] = true
+				}
+			}
+		}
+	}
+
+	return used, nil
+}
@@ -0,0 +1,303 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/deepanshumishra/devcost-api/internal/config"
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ListUnusedVPCResources identifies hourly-billed networking that carried no traffic between start
+// and end: interface VPC endpoints, Transit Gateway attachments, Client VPN endpoints and
+// Site-to-Site VPN connections. It also reports subnets that auto-assign public IPv4 addresses.
+func ListUnusedVPCResources(ctx context.Context, cfg *config.Config, start, end time.Time) ([]models.UnusedResource, error) {
+	client := ec2.NewFromConfig(cfg.AWSConfig)
+	cwClient := cloudwatch.NewFromConfig(cfg.AWSConfig)
+	unusedResources := []models.UnusedResource{}
+	days := int(end.Sub(start).Hours() / 24)
+
+	// Each check needs its own permissions; a failed one is logged and the others still report
+	var errors []error
+	for _, list := range []func(context.Context, *ec2.Client, *cloudwatch.Client, time.Time, time.Time, int) ([]models.UnusedResource, error){
+		listIdleInterfaceEndpoints,
+		listIdleTransitGatewayAttachments,
+		listIdleClientVPNEndpoints,
+		listIdleVPNConnections,
+	} {
+		resources, err := list(ctx, client, cwClient, start, end, days)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		unusedResources = append(unusedResources, resources...)
+	}
+
+	subnets, err := listAutoAssignedPublicIPv4(ctx, client)
+	if err != nil {
+		errors = append(errors, err)
+	} else {
+		unusedResources = append(unusedResources, subnets...)
+	}
+
+	if len(unusedResources) == 0 && len(errors) > 0 {
+		return nil, fmt.Errorf("failed to list unused VPC resources: %v", errors[0])
+	}
+	return unusedResources, nil
+}
+
+// listIdleInterfaceEndpoints finds available interface endpoints that processed no bytes. Each
+// endpoint is billed per hour in every subnet (Availability Zone) it is deployed to.
+func listIdleInterfaceEndpoints(ctx context.Context, client *ec2.Client, cwClient *cloudwatch.Client, start, end time.Time, days int) ([]models.UnusedResource, error) {
+	idleEndpoints := []models.UnusedResource{}
+
+	paginator := ec2.NewDescribeVpcEndpointsPaginator(client, &ec2.DescribeVpcEndpointsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("vpc-endpoint-type"), Values: []string{string(types.VpcEndpointTypeInterface)}},
+			{Name: aws.String("vpc-endpoint-state"), Values: []string{"available"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe VPC endpoints: %v", err)
+			return nil, fmt.Errorf("failed to describe VPC endpoints: %v", err)
+		}
+
+		for _, endpoint := range page.VpcEndpoints {
+			endpointID := aws.ToString(endpoint.VpcEndpointId)
+			dimensions := []cwtypes.Dimension{
+				{Name: aws.String("Endpoint Type"), Value: aws.String(string(endpoint.VpcEndpointType))},
+				{Name: aws.String("Service Name"), Value: endpoint.ServiceName},
+				{Name: aws.String("VPC Endpoint Id"), Value: endpoint.VpcEndpointId},
+				{Name: aws.String("VPC Id"), Value: endpoint.VpcId},
+			}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("bytes", "AWS/PrivateLinkEndpoints", "BytesProcessed", "Sum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for VPC endpoint %s: %v", endpointID, err)
+				continue
+			}
+			if sumValues(values["bytes"]) > 0 {
+				continue
+			}
+
+			idleEndpoints = append(idleEndpoints, models.UnusedResource{
+				ResourceType:         "ec2:vpc-endpoint",
+				ResourceID:           endpointID,
+				Reason:               fmt.Sprintf("No bytes processed for %d days (%s in %d AZs)", days, aws.ToString(endpoint.ServiceName), len(endpoint.SubnetIds)),
+				EstimatedMonthlyCost: vpcInterfaceEndpointHourlyPrice * float64(len(endpoint.SubnetIds)) * hoursPerMonth,
+			})
+			log.Printf("Found idle VPC endpoint: %s", endpointID)
+		}
+	}
+
+	return idleEndpoints, nil
+}
+
+// listIdleTransitGatewayAttachments finds available Transit Gateway attachments with no bytes in
+// or out.
+func listIdleTransitGatewayAttachments(ctx context.Context, client *ec2.Client, cwClient *cloudwatch.Client, start, end time.Time, days int) ([]models.UnusedResource, error) {
+	idleAttachments := []models.UnusedResource{}
+
+	paginator := ec2.NewDescribeTransitGatewayAttachmentsPaginator(client, &ec2.DescribeTransitGatewayAttachmentsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("state"), Values: []string{string(types.TransitGatewayAttachmentStateAvailable)}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Transit Gateway attachments: %v", err)
+			return nil, fmt.Errorf("failed to describe Transit Gateway attachments: %v", err)
+		}
+
+		for _, attachment := range page.TransitGatewayAttachments {
+			attachmentID := aws.ToString(attachment.TransitGatewayAttachmentId)
+			dimensions := []cwtypes.Dimension{
+				{Name: aws.String("TransitGateway"), Value: attachment.TransitGatewayId},
+				{Name: aws.String("TransitGatewayAttachment"), Value: attachment.TransitGatewayAttachmentId},
+			}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("bytes_in", "AWS/TransitGateway", "BytesIn", "Sum", dimensions, 86400, true),
+				metricQuery("bytes_out", "AWS/TransitGateway", "BytesOut", "Sum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Transit Gateway attachment %s: %v", attachmentID, err)
+				continue
+			}
+			if sumValues(values["bytes_in"])+sumValues(values["bytes_out"]) > 0 {
+				continue
+			}
+
+			idleAttachments = append(idleAttachments, models.UnusedResource{
+				ResourceType:         "ec2:transit-gateway-attachment",
+				ResourceID:           attachmentID,
+				Reason:               fmt.Sprintf("No traffic for %d days (%s %s)", days, attachment.ResourceType, aws.ToString(attachment.ResourceId)),
+				EstimatedMonthlyCost: transitGatewayAttachmentHourlyPrice * hoursPerMonth,
+			})
+			log.Printf("Found idle Transit Gateway attachment: %s", attachmentID)
+		}
+	}
+
+	return idleAttachments, nil
+}
+
+// listIdleClientVPNEndpoints finds Client VPN endpoints with associated subnets but no client
+// connections. Endpoints are billed per associated subnet hour.
+func listIdleClientVPNEndpoints(ctx context.Context, client *ec2.Client, cwClient *cloudwatch.Client, start, end time.Time, days int) ([]models.UnusedResource, error) {
+	idleEndpoints := []models.UnusedResource{}
+
+	paginator := ec2.NewDescribeClientVpnEndpointsPaginator(client, &ec2.DescribeClientVpnEndpointsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe Client VPN endpoints: %v", err)
+			return nil, fmt.Errorf("failed to describe Client VPN endpoints: %v", err)
+		}
+
+		for _, endpoint := range page.ClientVpnEndpoints {
+			endpointID := aws.ToString(endpoint.ClientVpnEndpointId)
+			if endpoint.Status == nil || endpoint.Status.Code != types.ClientVpnEndpointStatusCodeAvailable {
+				continue
+			}
+
+			associations := 0
+			networks := ec2.NewDescribeClientVpnTargetNetworksPaginator(client, &ec2.DescribeClientVpnTargetNetworksInput{ClientVpnEndpointId: endpoint.ClientVpnEndpointId})
+			for networks.HasMorePages() {
+				networkPage, err := networks.NextPage(ctx)
+				if err != nil {
+					log.Printf("Failed to describe target networks for Client VPN endpoint %s: %v", endpointID, err)
+					break
+				}
+				for _, network := range networkPage.ClientVpnTargetNetworks {
+					if network.Status != nil && network.Status.Code == types.AssociationStatusCodeAssociated {
+						associations++
+					}
+				}
+			}
+			if associations == 0 {
+				continue
+			}
+
+			dimensions := []cwtypes.Dimension{{Name: aws.String("Endpoint"), Value: endpoint.ClientVpnEndpointId}}
+			values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+				metricQuery("connections", "AWS/ClientVPN", "ActiveConnectionsCount", "Maximum", dimensions, 86400, true),
+			}, start, end)
+			if err != nil {
+				log.Printf("Failed to get metrics for Client VPN endpoint %s: %v", endpointID, err)
+				continue
+			}
+			if maxValue(values["connections"]) > 0 {
+				continue
+			}
+
+			idleEndpoints = append(idleEndpoints, models.UnusedResource{
+				ResourceType:         "ec2:client-vpn-endpoint",
+				ResourceID:           endpointID,
+				Reason:               fmt.Sprintf("No client connections for %d days on %d associated subnets", days, associations),
+				EstimatedMonthlyCost: clientVPNAssociationHourlyPrice * float64(associations) * hoursPerMonth,
+			})
+			log.Printf("Found idle Client VPN endpoint: %s", endpointID)
+		}
+	}
+
+	return idleEndpoints, nil
+}
+
+// listIdleVPNConnections finds available Site-to-Site VPN connections with no tunnel traffic.
+func listIdleVPNConnections(ctx context.Context, client *ec2.Client, cwClient *cloudwatch.Client, start, end time.Time, days int) ([]models.UnusedResource, error) {
+	idleConnections := []models.UnusedResource{}
+
+	result, err := client.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("state"), Values: []string{string(types.VpnStateAvailable)}},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to describe VPN connections: %v", err)
+		return nil, fmt.Errorf("failed to describe VPN connections: %v", err)
+	}
+
+	for _, connection := range result.VpnConnections {
+		connectionID := aws.ToString(connection.VpnConnectionId)
+		dimensions := []cwtypes.Dimension{{Name: aws.String("VpnId"), Value: connection.VpnConnectionId}}
+		values, err := getMetricData(ctx, cwClient, []cwtypes.MetricDataQuery{
+			metricQuery("data_in", "AWS/VPN", "TunnelDataIn", "Sum", dimensions, 86400, true),
+			metricQuery("data_out", "AWS/VPN", "TunnelDataOut", "Sum", dimensions, 86400, true),
+		}, start, end)
+		if err != nil {
+			log.Printf("Failed to get metrics for VPN connection %s: %v", connectionID, err)
+			continue
+		}
+		if sumValues(values["data_in"])+sumValues(values["data_out"]) > 0 {
+			continue
+		}
+
+		tunnelsUp := 0
+		for _, tunnel := range connection.VgwTelemetry {
+			if tunnel.Status == types.TelemetryStatusUp {
+				tunnelsUp++
+			}
+		}
+		idleConnections = append(idleConnections, models.UnusedResource{
+			ResourceType:         "ec2:vpn-connection",
+			ResourceID:           connectionID,
+			Reason:               fmt.Sprintf("No tunnel traffic for %d days (%d of %d tunnels up)", days, tunnelsUp, len(connection.VgwTelemetry)),
+			EstimatedMonthlyCost: siteToSiteVPNHourlyPrice * hoursPerMonth,
+		})
+		log.Printf("Found idle VPN connection: %s", connectionID)
+	}
+
+	return idleConnections, nil
+}
+
+// listAutoAssignedPublicIPv4 reports subnets whose network interfaces hold Amazon-owned public
+// IPv4 addresses, which are billed hourly like Elastic IPs. Elastic IPs are covered by
+// ListUnusedEC2Resources.
+func listAutoAssignedPublicIPv4(ctx context.Context, client *ec2.Client) ([]models.UnusedResource, error) {
+	addressesBySubnet := make(map[string][]string)
+
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(client, &ec2.DescribeNetworkInterfacesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Failed to describe network interfaces: %v", err)
+			return nil, fmt.Errorf("failed to describe network interfaces: %v", err)
+		}
+
+		for _, networkInterface := range page.NetworkInterfaces {
+			association := networkInterface.Association
+			// Load balancers and NAT Gateways also use interfaces; only instance IPs can be
+			// turned off with the subnet's auto-assign setting
+			if association == nil || association.AllocationId != nil || aws.ToString(association.IpOwnerId) != "amazon" ||
+				networkInterface.Attachment == nil || networkInterface.Attachment.InstanceId == nil {
+				continue
+			}
+			subnetID := aws.ToString(networkInterface.SubnetId)
+			addressesBySubnet[subnetID] = append(addressesBySubnet[subnetID], aws.ToString(networkInterface.Attachment.InstanceId))
+		}
+	}
+
+	subnets := []models.UnusedResource{}
+	for subnetID, instanceIDs := range addressesBySubnet {
+		subnets = append(subnets, models.UnusedResource{
+			ResourceType:         "ec2:subnet",
+			ResourceID:           subnetID,
+			Reason:               fmt.Sprintf("%d auto-assigned public IPv4 addresses billed hourly (%s)", len(instanceIDs), strings.Join(instanceIDs, ", ")),
+			EstimatedMonthlyCost: publicIPv4HourlyPrice * float64(len(instanceIDs)) * hoursPerMonth,
+		})
+		log.Printf("Found subnet with auto-assigned public IPv4 addresses: %s", subnetID)
+	}
+
+	return subnets, nil
+}
@@ -0,0 +1,110 @@
+package budget
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// DefaultThresholds alert at 50%, 80% and 100% of actual spend and 100% of forecasted spend.
+var DefaultThresholds = []models.BudgetThreshold{
+	{Percent: 50, Type: "actual"},
+	{Percent: 80, Type: "actual"},
+	{Percent: 100, Type: "actual"},
+	{Percent: 100, Type: "forecast"},
+}
+
+// Validate checks a budget definition and fills in default thresholds. Budgets without a
+// currency are tracked in the billing currency.
+func Validate(b *models.Budget) error {
+	if b.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch b.Scope.Type {
+	case "tag":
+		if b.Scope.Key == "" {
+			return fmt.Errorf("scope.key is required for tag budgets")
+		}
+	case "account", "service":
+	default:
+		return fmt.Errorf("scope.type must be tag, account or service")
+	}
+	if b.Scope.Value == "" {
+		return fmt.Errorf("scope.value is required")
+	}
+	if b.MonthlyLimit <= 0 {
+		return fmt.Errorf("monthly_limit must be positive")
+	}
+	if len(b.Thresholds) == 0 {
+		b.Thresholds = append([]models.BudgetThreshold(nil), DefaultThresholds...)
+	}
+	for _, threshold := range b.Thresholds {
+		if threshold.Percent <= 0 {
+			return fmt.Errorf("threshold percent must be positive")
+		}
+		if threshold.Type != "actual" && threshold.Type != "forecast" {
+			return fmt.Errorf("threshold type must be actual or forecast")
+		}
+	}
+	return nil
+}
+
+// MonthBounds returns the first day of the month containing t and of the following month.
+func MonthBounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Evaluate compares month-to-date and forecasted spend against the budget's thresholds and
+// returns alerts for thresholds crossed that have not fired yet this month. Fired alerts are
+// recorded on the budget.
+func Evaluate(b *models.Budget, month string, actual, forecast float64, now time.Time) []models.BudgetAlert {
+	alerts := []models.BudgetAlert{}
+	if b.FiredAlerts == nil {
+		b.FiredAlerts = make(map[string]time.Time)
+	}
+
+	for _, threshold := range b.Thresholds {
+		spend := actual
+		if threshold.Type == "forecast" {
+			spend = forecast
+		}
+		if spend < b.MonthlyLimit*threshold.Percent/100 {
+			continue
+		}
+
+		key := month + ":" + threshold.Type + ":" + strconv.FormatFloat(threshold.Percent, 'f', -1, 64)
+		if _, fired := b.FiredAlerts[key]; fired {
+			continue
+		}
+		b.FiredAlerts[key] = now
+
+		alerts = append(alerts, models.BudgetAlert{
+			BudgetID:  b.ID,
+			Budget:    b.Name,
+			Month:     month,
+			Threshold: threshold.Percent,
+			Type:      threshold.Type,
+			Spend:     spend,
+			Limit:     b.MonthlyLimit,
+			Currency:  b.Currency,
+			FiredAt:   now,
+		})
+	}
+
+	return alerts
+}
+
+// ProjectLinear extrapolates month-to-date spend to the full month at the average daily rate so far.
+func ProjectLinear(actual float64, monthStart, monthEnd, now time.Time) float64 {
+	elapsed := now.Sub(monthStart)
+	if elapsed <= 0 {
+		return actual
+	}
+	if now.After(monthEnd) {
+		return actual
+	}
+	return actual * float64(monthEnd.Sub(monthStart)) / float64(elapsed)
+}
@@ -0,0 +1,78 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	b := &models.Budget{
+		ID:           "b1",
+		Name:         "dev-cluster",
+		Scope:        models.BudgetScope{Type: "tag", Key: "project", Value: "dev-cluster"},
+		MonthlyLimit: 100,
+	}
+	if err := Validate(b); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	now := time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC)
+	alerts := Evaluate(b, "2025-05", 60, 110, now)
+	if len(alerts) != 2 {
+		t.Fatalf("expected 50%% actual and 100%% forecast alerts, got %d", len(alerts))
+	}
+
+	// Thresholds already crossed this month do not fire again
+	alerts = Evaluate(b, "2025-05", 85, 120, now)
+	if len(alerts) != 1 || alerts[0].Threshold != 80 || alerts[0].Type != "actual" {
+		t.Fatalf("expected only the 80%% actual alert, got %+v", alerts)
+	}
+
+	// A new month starts afresh
+	alerts = Evaluate(b, "2025-06", 60, 60, now.AddDate(0, 1, 0))
+	if len(alerts) != 1 {
+		t.Fatalf("expected the 50%% actual alert in the new month, got %d", len(alerts))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget models.Budget
+	}{
+		{"missing name", models.Budget{Scope: models.BudgetScope{Type: "service", Value: "Amazon S3"}, MonthlyLimit: 10}},
+		{"tag scope without key", models.Budget{Name: "b", Scope: models.BudgetScope{Type: "tag", Value: "dev"}, MonthlyLimit: 10}},
+		{"unknown scope", models.Budget{Name: "b", Scope: models.BudgetScope{Type: "region", Value: "us-east-1"}, MonthlyLimit: 10}},
+		{"zero limit", models.Budget{Name: "b", Scope: models.BudgetScope{Type: "account", Value: "123456789012"}}},
+		{"bad threshold type", models.Budget{Name: "b", Scope: models.BudgetScope{Type: "account", Value: "123456789012"}, MonthlyLimit: 10,
+			Thresholds: []models.BudgetThreshold{{Percent: 90, Type: "projected"}}}},
+	}
+
+	for _, tt := range tests {
+		if err := Validate(&tt.budget); err == nil {
+			t.Errorf("%s: expected validation error", tt.name)
+		}
+	}
+}
+
+func TestStoreReturnsCopies(t *testing.T) {
+	store, _ := NewStore("")
+	b := models.Budget{Name: "dev-cluster", Scope: models.BudgetScope{Type: "service", Value: "Amazon S3"}, MonthlyLimit: 10}
+	if err := Validate(&b); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	created, _ := store.Create(b)
+	if err := store.RecordAlerts(created.ID, map[string]time.Time{"2025-05:actual:50": time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Evaluating a listed budget must not change the stored alert history
+	listed := store.List()[0]
+	Evaluate(&listed, "2025-05", 10, 10, time.Now())
+	stored, _ := store.Get(created.ID)
+	if len(stored.FiredAlerts) != 1 {
+		t.Fatalf("expected 1 stored alert, got %d", len(stored.FiredAlerts))
+	}
+}
@@ -0,0 +1,73 @@
+package budget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// Notifier delivers budget alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert models.BudgetAlert) error
+}
+
+// LogNotifier writes alerts to the log.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alert models.BudgetAlert) error {
+	log.Printf("Budget alert: %s", alertText(alert))
+	return nil
+}
+
+// WebhookNotifier posts alerts as JSON to a webhook URL. The payload includes a "text"
+// field so Slack incoming webhooks render it directly.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w WebhookNotifier) Notify(ctx context.Context, alert models.BudgetAlert) error {
+	payload, err := json.Marshal(struct {
+		Text  string             `json:"text"`
+		Alert models.BudgetAlert `json:"alert"`
+	}{Text: alertText(alert), Alert: alert})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send budget alert: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("budget alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// alertText formats an alert as a one-line message.
+func alertText(alert models.BudgetAlert) string {
+	kind := "Actual"
+	if alert.Type == "forecast" {
+		kind = "Forecasted"
+	}
+	return fmt.Sprintf("%s spend for budget %q in %s is %.2f %s, %.0f%% threshold of %.2f %s reached",
+		kind, alert.Budget, alert.Month, alert.Spend, alert.Currency, alert.Threshold, alert.Limit, alert.Currency)
+}
@@ -0,0 +1,169 @@
+package budget
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// ErrNotFound is returned for unknown budget IDs.
+var ErrNotFound = fmt.Errorf("budget not found")
+
+// Store keeps budgets in memory, persisting them as JSON when created with a path.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	budgets map[string]models.Budget
+}
+
+// NewStore creates a store, loading previously saved budgets from path if it exists.
+// An empty path keeps budgets in memory only.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, budgets: make(map[string]models.Budget)}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budgets from %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &s.budgets); err != nil {
+		return nil, fmt.Errorf("failed to parse budgets from %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// List returns all budgets ordered by name.
+func (s *Store) List() []models.Budget {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	budgets := make([]models.Budget, 0, len(s.budgets))
+	for _, b := range s.budgets {
+		budgets = append(budgets, cloneBudget(b))
+	}
+	sort.Slice(budgets, func(i, j int) bool { return budgets[i].Name < budgets[j].Name })
+	return budgets
+}
+
+// Get returns the budget with the given ID.
+func (s *Store) Get(id string) (models.Budget, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.budgets[id]
+	if !ok {
+		return models.Budget{}, ErrNotFound
+	}
+	return cloneBudget(b), nil
+}
+
+// Create assigns an ID to a new budget and stores it.
+func (s *Store) Create(b models.Budget) (models.Budget, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return models.Budget{}, fmt.Errorf("failed to generate budget ID: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	b.ID = hex.EncodeToString(id)
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	b.FiredAlerts = nil
+	s.budgets[b.ID] = b
+	return cloneBudget(b), s.save()
+}
+
+// Update replaces the definition of an existing budget, keeping its alert history.
+func (s *Store) Update(id string, b models.Budget) (models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.budgets[id]
+	if !ok {
+		return models.Budget{}, ErrNotFound
+	}
+	b.ID = id
+	b.CreatedAt = existing.CreatedAt
+	b.UpdatedAt = time.Now().UTC()
+	b.FiredAlerts = existing.FiredAlerts
+	s.budgets[id] = b
+	return cloneBudget(b), s.save()
+}
+
+// RecordAlerts saves the alert history of a budget after evaluation. The history is only
+// changed when it is saved, so alerts that fail to save fire again on the next evaluation.
+func (s *Store) RecordAlerts(id string, firedAlerts map[string]time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.budgets[id]
+	if !ok {
+		return ErrNotFound
+	}
+	b := previous
+	b.FiredAlerts = make(map[string]time.Time, len(firedAlerts))
+	for key, firedAt := range firedAlerts {
+		b.FiredAlerts[key] = firedAt
+	}
+	s.budgets[id] = b
+	if err := s.save(); err != nil {
+		s.budgets[id] = previous
+		return err
+	}
+	return nil
+}
+
+// Delete removes a budget.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.budgets[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.budgets, id)
+	return s.save()
+}
+
+// cloneBudget copies a budget so callers can change its thresholds and alert history without
+// touching the stored budget.
+func cloneBudget(b models.Budget) models.Budget {
+	b.Thresholds = append([]models.BudgetThreshold(nil), b.Thresholds...)
+	if b.FiredAlerts != nil {
+		firedAlerts := make(map[string]time.Time, len(b.FiredAlerts))
+		for key, firedAt := range b.FiredAlerts {
+			firedAlerts[key] = firedAt
+		}
+		b.FiredAlerts = firedAlerts
+	}
+	return b
+}
+
+// save persists budgets to disk; callers must hold the write lock.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.budgets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode budgets: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create budgets directory: %v", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write budgets: %v", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,230 @@
+package chargeback
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// Unallocated names the statement that collects costs no cost center or shared cost rule claims.
+const Unallocated = "Unallocated"
+
+// CostRow is one month's cost for a tag value and service. Credits, refunds and discounts are
+// reported as separate rows with Credit set and a negative cost.
+type CostRow struct {
+	TagValue string
+	Service  string
+	Cost     float64
+	Credit   bool
+}
+
+// Validate checks chargeback rules for consistency.
+func Validate(rules models.ChargebackRules) error {
+	if rules.TagKey == "" {
+		return fmt.Errorf("tag_key is required")
+	}
+	if len(rules.CostCenters) == 0 {
+		return fmt.Errorf("cost_centers must contain at least one entry")
+	}
+
+	centers := make(map[string]bool)
+	tagValues := make(map[string]string)
+	for _, center := range rules.CostCenters {
+		if center.Name == "" {
+			return fmt.Errorf("cost center name is required")
+		}
+		if center.Name == Unallocated || centers[center.Name] {
+			return fmt.Errorf("cost center name '%s' is reserved or duplicated", center.Name)
+		}
+		centers[center.Name] = true
+		if center.DiscountPercent < 0 || center.DiscountPercent > 100 {
+			return fmt.Errorf("discount_percent for cost center '%s' must be between 0 and 100", center.Name)
+		}
+		for _, value := range center.TagValues {
+			if owner, ok := tagValues[value]; ok {
+				return fmt.Errorf("tag value '%s' is mapped to both '%s' and '%s'", value, owner, center.Name)
+			}
+			tagValues[value] = center.Name
+		}
+	}
+
+	for _, rule := range rules.SharedCosts {
+		if len(rule.Services) == 0 && !rule.Untagged {
+			return fmt.Errorf("shared cost rule '%s' must match services or untagged costs", rule.Name)
+		}
+		switch rule.Method {
+		case "proportional":
+		case "fixed":
+			total := 0.0
+			for center, ratio := range rule.Ratios {
+				if !centers[center] {
+					return fmt.Errorf("shared cost rule '%s' references unknown cost center '%s'", rule.Name, center)
+				}
+				if ratio < 0 {
+					return fmt.Errorf("shared cost rule '%s' has a negative ratio", rule.Name)
+				}
+				total += ratio
+			}
+			if total == 0 {
+				return fmt.Errorf("shared cost rule '%s' needs ratios for a fixed split", rule.Name)
+			}
+		default:
+			return fmt.Errorf("shared cost rule '%s' method must be proportional or fixed", rule.Name)
+		}
+	}
+	return nil
+}
+
+// Build produces one statement per cost center for a month. Costs of mapped tag values are charged
+// directly; costs matching a shared cost rule are split proportionally to direct cost or by fixed
+// ratios. Credits follow the tag value that earned them, and unattributable credits are split
+// proportionally. Costs nobody claims appear on an Unallocated statement.
+func Build(rules models.ChargebackRules, month, currency string, rows []CostRow) []models.ChargebackStatement {
+	owner := make(map[string]string)
+	for _, center := range rules.CostCenters {
+		for _, value := range center.TagValues {
+			owner[value] = center.Name
+		}
+	}
+
+	lines := make(map[string]map[string]*models.ChargebackLineItem)
+	line := func(center, service string) *models.ChargebackLineItem {
+		if lines[center] == nil {
+			lines[center] = make(map[string]*models.ChargebackLineItem)
+		}
+		if lines[center][service] == nil {
+			lines[center][service] = &models.ChargebackLineItem{Service: service}
+		}
+		return lines[center][service]
+	}
+
+	shared := make([]map[string]float64, len(rules.SharedCosts))
+	sharedCredits := make(map[string]float64)
+	direct := make(map[string]float64)
+
+	for _, row := range rows {
+		center, mapped := owner[row.TagValue]
+		if row.Credit {
+			if mapped {
+				line(center, row.Service).Credits += row.Cost
+			} else {
+				sharedCredits[row.Service] += row.Cost
+			}
+			continue
+		}
+
+		if i := matchSharedRule(rules.SharedCosts, row.Service, mapped); i >= 0 {
+			if shared[i] == nil {
+				shared[i] = make(map[string]float64)
+			}
+			shared[i][row.Service] += row.Cost
+			continue
+		}
+		if !mapped {
+			center = Unallocated
+		}
+		line(center, row.Service).DirectCost += row.Cost
+		if mapped {
+			direct[center] += row.Cost
+		}
+	}
+
+	proportional := proportionalWeights(rules.CostCenters, direct)
+	for i, rule := range rules.SharedCosts {
+		weights := proportional
+		if rule.Method == "fixed" {
+			weights = normalize(rule.Ratios)
+		}
+		for service, cost := range shared[i] {
+			for center, weight := range weights {
+				line(center, service).SharedCost += cost * weight
+			}
+		}
+	}
+	for service, credit := range sharedCredits {
+		for center, weight := range proportional {
+			line(center, service).Credits += credit * weight
+		}
+	}
+
+	centers := append(append([]models.CostCenter{}, rules.CostCenters...), models.CostCenter{Name: Unallocated})
+	statements := []models.ChargebackStatement{}
+	for _, center := range centers {
+		if center.Name == Unallocated && len(lines[Unallocated]) == 0 {
+			continue
+		}
+		statement := models.ChargebackStatement{
+			CostCenter: center.Name,
+			Month:      month,
+			Currency:   currency,
+			TagValues:  center.TagValues,
+			LineItems:  []models.ChargebackLineItem{},
+		}
+		if statement.TagValues == nil {
+			statement.TagValues = []string{}
+		}
+		for _, item := range lines[center.Name] {
+			item.Total = item.DirectCost + item.SharedCost + item.Credits
+			statement.DirectCost += item.DirectCost
+			statement.SharedCost += item.SharedCost
+			statement.Credits += item.Credits
+			statement.LineItems = append(statement.LineItems, *item)
+		}
+		sort.Slice(statement.LineItems, func(i, j int) bool {
+			return statement.LineItems[i].Service < statement.LineItems[j].Service
+		})
+		subtotal := statement.DirectCost + statement.SharedCost + statement.Credits
+		statement.Discount = -subtotal * center.DiscountPercent / 100
+		statement.Total = subtotal + statement.Discount
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
+
+// matchSharedRule returns the index of the first shared cost rule matching a service, or -1.
+func matchSharedRule(rules []models.SharedCostRule, service string, mapped bool) int {
+	for i, rule := range rules {
+		if rule.Untagged && !mapped {
+			return i
+		}
+		for _, s := range rule.Services {
+			if s == service {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// proportionalWeights splits by each cost center's share of direct cost, or evenly when there is none.
+func proportionalWeights(centers []models.CostCenter, direct map[string]float64) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, center := range centers {
+		if direct[center.Name] > 0 {
+			weights[center.Name] = direct[center.Name]
+		}
+	}
+	if len(weights) == 0 {
+		for _, center := range centers {
+			weights[center.Name] = 1
+		}
+	}
+	return normalize(weights)
+}
+
+// normalize scales ratios so they sum to one.
+func normalize(ratios map[string]float64) map[string]float64 {
+	total := 0.0
+	for _, ratio := range ratios {
+		total += ratio
+	}
+	weights := make(map[string]float64)
+	for center, ratio := range ratios {
+		if ratio > 0 {
+			weights[center] = ratio / total
+		}
+	}
+	return weights
+}
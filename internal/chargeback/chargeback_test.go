@@ -0,0 +1,81 @@
+package chargeback
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+func TestBuild(t *testing.T) {
+	rules := models.ChargebackRules{
+		TagKey: "team",
+		CostCenters: []models.CostCenter{
+			{Name: "payments", TagValues: []string{"payments", "billing"}},
+			{Name: "search", TagValues: []string{"search"}, DiscountPercent: 10},
+		},
+		SharedCosts: []models.SharedCostRule{
+			{Name: "support", Services: []string{"AWS Support (Business)"}, Method: "fixed", Ratios: map[string]float64{"payments": 1, "search": 3}},
+			{Name: "untagged", Untagged: true, Method: "proportional"},
+		},
+	}
+	if err := Validate(rules); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	rows := []CostRow{
+		{TagValue: "payments", Service: "Amazon EC2", Cost: 60},
+		{TagValue: "billing", Service: "Amazon RDS", Cost: 15},
+		{TagValue: "search", Service: "Amazon EC2", Cost: 25},
+		{TagValue: "", Service: "Amazon S3", Cost: 20},
+		{TagValue: "", Service: "AWS Support (Business)", Cost: 40},
+		{TagValue: "search", Service: "Amazon EC2", Cost: -5, Credit: true},
+		{TagValue: "", Service: "Amazon EC2", Cost: -10, Credit: true},
+	}
+	statements := Build(rules, "2025-05", "USD", rows)
+	if len(statements) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(statements))
+	}
+
+	// payments: 75 direct, 10 support (1/4), 15 untagged (75%), -7.5 shared credit
+	payments := statements[0]
+	want := map[string]float64{"direct": 75, "shared": 25, "credits": -7.5, "total": 92.5}
+	got := map[string]float64{"direct": payments.DirectCost, "shared": payments.SharedCost, "credits": payments.Credits, "total": payments.Total}
+	for field, value := range want {
+		if math.Abs(got[field]-value) > 1e-9 {
+			t.Errorf("payments %s: expected %f, got %f", field, value, got[field])
+		}
+	}
+
+	// search: 25 direct, 30 support (3/4), 5 untagged (25%), -5 direct and -2.5 shared credit, 10% discount
+	search := statements[1]
+	if math.Abs(search.SharedCost-35) > 1e-9 || math.Abs(search.Credits+7.5) > 1e-9 {
+		t.Errorf("search: expected shared 35 and credits -7.5, got %f and %f", search.SharedCost, search.Credits)
+	}
+	if math.Abs(search.Discount+5.25) > 1e-9 || math.Abs(search.Total-47.25) > 1e-9 {
+		t.Errorf("search: expected discount -5.25 and total 47.25, got %f and %f", search.Discount, search.Total)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, search); err != nil {
+		t.Fatalf("unexpected PDF error: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) || !bytes.HasSuffix(buf.Bytes(), []byte("%%EOF\n")) {
+		t.Errorf("expected a complete PDF document")
+	}
+}
+
+func TestBuildUnallocated(t *testing.T) {
+	rules := models.ChargebackRules{
+		TagKey:      "team",
+		CostCenters: []models.CostCenter{{Name: "payments", TagValues: []string{"payments"}}},
+	}
+	statements := Build(rules, "2025-05", "USD", []CostRow{
+		{TagValue: "payments", Service: "Amazon EC2", Cost: 10},
+		{TagValue: "legacy", Service: "Amazon EC2", Cost: 4},
+	})
+	if len(statements) != 2 || statements[1].CostCenter != Unallocated || statements[1].Total != 4 {
+		t.Fatalf("expected unmapped costs on an Unallocated statement, got %+v", statements)
+	}
+}
@@ -0,0 +1,60 @@
+package chargeback
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// WriteCSV writes a statement's line items as CSV followed by a total row.
+func WriteCSV(w io.Writer, statement models.ChargebackStatement) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"cost_center", "month", "service", "direct_cost", "shared_cost", "credits", "total", "currency"}); err != nil {
+		return err
+	}
+
+	for _, item := range statement.LineItems {
+		record := []string{
+			statement.CostCenter,
+			statement.Month,
+			item.Service,
+			formatAmount(item.DirectCost),
+			formatAmount(item.SharedCost),
+			formatAmount(item.Credits),
+			formatAmount(item.Total),
+			statement.Currency,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	if statement.Discount != 0 {
+		record := []string{statement.CostCenter, statement.Month, "Discount", "", "", "", formatAmount(statement.Discount), statement.Currency}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	total := []string{
+		statement.CostCenter,
+		statement.Month,
+		"Total",
+		formatAmount(statement.DirectCost),
+		formatAmount(statement.SharedCost),
+		formatAmount(statement.Credits),
+		formatAmount(statement.Total),
+		statement.Currency,
+	}
+	if err := writer.Write(total); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatAmount renders a cost to the cent.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,112 @@
+package chargeback
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// pdfLinesPerPage fits 9pt text with 14pt leading between 0.75in margins on US Letter.
+const pdfLinesPerPage = 46
+
+// WritePDF renders a statement as a plain-text PDF using the built-in Courier font, so the
+// columns line up without embedding fonts.
+func WritePDF(w io.Writer, statement models.ChargebackStatement) error {
+	lines := []string{
+		"Chargeback statement",
+		"",
+		fmt.Sprintf("Cost center: %s", statement.CostCenter),
+		fmt.Sprintf("Month:       %s", statement.Month),
+		fmt.Sprintf("Currency:    %s", statement.Currency),
+		fmt.Sprintf("Tag values:  %s", strings.Join(statement.TagValues, ", ")),
+		"",
+		fmt.Sprintf("%-36s %12s %12s %12s %12s", "Service", "Direct", "Shared", "Credits", "Total"),
+		strings.Repeat("-", 88),
+	}
+	for _, item := range statement.LineItems {
+		lines = append(lines, fmt.Sprintf("%-36s %12.2f %12.2f %12.2f %12.2f",
+			truncate(item.Service, 36), item.DirectCost, item.SharedCost, item.Credits, item.Total))
+	}
+	lines = append(lines, strings.Repeat("-", 88))
+	if statement.Discount != 0 {
+		lines = append(lines, fmt.Sprintf("%-36s %51.2f", "Discount", statement.Discount))
+	}
+	lines = append(lines, fmt.Sprintf("%-36s %12.2f %12.2f %12.2f %12.2f",
+		"Total", statement.DirectCost, statement.SharedCost, statement.Credits, statement.Total))
+
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	// Objects: 1 catalog, 2 page tree, 3 font, then a page and content stream per page
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	)
+	for i, page := range pages {
+		var content bytes.Buffer
+		content.WriteString("BT /F1 9 Tf 14 TL 54 738 Td\n")
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDF(line))
+		}
+		content.WriteString("ET")
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// escapePDF escapes a string for a PDF literal, replacing characters outside printable ASCII.
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most n characters.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "~"
+}
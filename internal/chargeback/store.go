@@ -0,0 +1,80 @@
+package chargeback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// Store holds the chargeback rules, persisting them as JSON when created with a path.
+type Store struct {
+	mu    sync.RWMutex
+	path  string
+	rules *models.ChargebackRules
+}
+
+// NewStore creates a store, loading previously saved rules from path if it exists.
+// An empty path keeps rules in memory only.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chargeback rules from %s: %v", path, err)
+	}
+	var rules models.ChargebackRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse chargeback rules from %s: %v", path, err)
+	}
+	if err := Validate(rules); err != nil {
+		return nil, fmt.Errorf("invalid chargeback rules in %s: %v", path, err)
+	}
+	s.rules = &rules
+	return s, nil
+}
+
+// Rules returns the configured rules and whether any have been set.
+func (s *Store) Rules() (models.ChargebackRules, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.rules == nil {
+		return models.ChargebackRules{}, false
+	}
+	return *s.rules, true
+}
+
+// SetRules replaces the rules after validating them.
+func (s *Store) SetRules(rules models.ChargebackRules) error {
+	if err := Validate(rules); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = &rules
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode chargeback rules: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create chargeback rules directory: %v", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write chargeback rules: %v", err)
+	}
+	return os.Rename(tmp, s.path)
+}
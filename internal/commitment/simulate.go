@@ -0,0 +1,70 @@
+package commitment
+
+import (
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// DefaultDiscountRates approximate the average Compute Savings Plans discount off on-demand
+// rates per term. Callers can pass their own rate for other plan types or negotiated pricing.
+var DefaultDiscountRates = map[string]float64{
+	"ONE_YEAR":    0.28,
+	"THREE_YEARS": 0.46,
+}
+
+// MaxSimulationRange is the longest period a simulation covers, the longest commitment term.
+const MaxSimulationRange = 3 * 365 * 24 * time.Hour
+
+// Simulate models a commitment of hourlyCommitment per hour against hourly on-demand spend.
+// Each hour the commitment pays for up to hourlyCommitment/(1-discountRate) of on-demand usage;
+// the commitment is charged in full whether used or not, and usage beyond it stays on-demand.
+func Simulate(hourly []float64, hourlyCommitment, discountRate float64) models.CommitmentSimulation {
+	result := models.CommitmentSimulation{
+		Hours:            len(hourly),
+		HourlyCommitment: hourlyCommitment,
+		DiscountRate:     discountRate,
+	}
+	capacity := hourlyCommitment / (1 - discountRate)
+
+	for _, onDemand := range hourly {
+		covered := onDemand
+		if covered > capacity {
+			covered = capacity
+		}
+		result.OnDemandCost += onDemand
+		result.CoveredOnDemandCost += covered
+		result.UncoveredOnDemandCost += onDemand - covered
+		result.CommitmentCost += hourlyCommitment
+	}
+
+	result.TotalCostWithCommitment = result.CommitmentCost + result.UncoveredOnDemandCost
+	result.EstimatedSavings = result.OnDemandCost - result.TotalCostWithCommitment
+	if result.OnDemandCost > 0 {
+		result.SavingsPercentage = result.EstimatedSavings / result.OnDemandCost * 100
+		result.Coverage = result.CoveredOnDemandCost / result.OnDemandCost * 100
+	}
+	if result.CommitmentCost > 0 {
+		result.Utilization = result.CoveredOnDemandCost * (1 - discountRate) / result.CommitmentCost * 100
+	}
+	return result
+}
+
+// SpreadHourly adds cost to the hourly buckets starting at start, spreading line items that span
+// several hours (e.g. daily CUR granularity) evenly across them.
+func SpreadHourly(hourly []float64, start, usageStart, usageEnd time.Time, cost float64) {
+	first := int(usageStart.Sub(start) / time.Hour)
+	last := int((usageEnd.Sub(start) - 1) / time.Hour)
+	if !usageEnd.After(usageStart) {
+		last = first
+	}
+	if last < first {
+		last = first
+	}
+	share := cost / float64(last-first+1)
+	for i := first; i <= last; i++ {
+		if i >= 0 && i < len(hourly) {
+			hourly[i] += share
+		}
+	}
+}
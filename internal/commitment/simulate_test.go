@@ -0,0 +1,43 @@
+package commitment
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSimulate(t *testing.T) {
+	// $1/hour commitment at a 50% discount covers up to $2/hour of on-demand usage
+	hourly := []float64{3, 2, 1, 0}
+	result := Simulate(hourly, 1, 0.5)
+
+	checks := map[string][2]float64{
+		"on demand":    {result.OnDemandCost, 6},
+		"commitment":   {result.CommitmentCost, 4},
+		"covered":      {result.CoveredOnDemandCost, 5},
+		"uncovered":    {result.UncoveredOnDemandCost, 1},
+		"total":        {result.TotalCostWithCommitment, 5},
+		"savings":      {result.EstimatedSavings, 1},
+		"utilization":  {result.Utilization, 62.5},
+		"coverage":     {result.Coverage, 5.0 / 6 * 100},
+		"savings rate": {result.SavingsPercentage, 1.0 / 6 * 100},
+	}
+	for name, check := range checks {
+		if math.Abs(check[0]-check[1]) > 1e-9 {
+			t.Errorf("%s: expected %f, got %f", name, check[1], check[0])
+		}
+	}
+}
+
+func TestSpreadHourly(t *testing.T) {
+	start := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	hourly := make([]float64, 48)
+
+	// A daily line item is spread over its 24 hours; an hourly one lands in its own bucket
+	SpreadHourly(hourly, start, start, start.Add(24*time.Hour), 48)
+	SpreadHourly(hourly, start, start.Add(30*time.Hour), start.Add(31*time.Hour), 5)
+
+	if hourly[0] != 2 || hourly[23] != 2 || hourly[24] != 0 || hourly[30] != 5 {
+		t.Errorf("unexpected hourly spread: %v", hourly)
+	}
+}
@@ -4,14 +4,40 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/deepanshumishra/devcost-api/internal/budget"
+	"github.com/deepanshumishra/devcost-api/internal/chargeback"
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+	"github.com/deepanshumishra/devcost-api/internal/currency"
 )
 
 type Config struct {
 	AWSConfig aws.Config
+	// APIKeys maps an API key to the role it grants (e.g. "admin").
+	APIKeys map[string]string
+	// CURDataDir is a local directory holding Cost and Usage Report exports.
+	CURDataDir string
+	// CURS3Bucket and CURS3Prefix locate CUR exports in S3. CURS3Endpoint overrides
+	// the S3 endpoint for S3-compatible stand-ins such as MinIO.
+	CURS3Bucket   string
+	CURS3Prefix   string
+	CURS3Endpoint string
+	// CURStore holds ingested CUR line items.
+	CURStore *cur.Store
+	// CostSource is the default source for cost endpoints: "cost_explorer" or "cur".
+	CostSource string
+	// BudgetStore holds budget definitions and their alert history.
+	BudgetStore *budget.Store
+	// BudgetWebhookURL receives budget alerts as JSON (Slack-compatible); alerts are always logged.
+	BudgetWebhookURL string
+	// ChargebackStore holds the rules mapping tag values to cost centers.
+	ChargebackStore *chargeback.Store
+	// CurrencyRates converts costs for the currency parameter; nil when no rates are configured.
+	CurrencyRates currency.Provider
 }
 
 func NewConfig() (*Config, error) {
@@ -46,9 +72,80 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %v", err)
 	}
 
+	apiKeys, err := parseAPIKeys(os.Getenv("DEVCOST_API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	// CUR_STORE_PATH persists ingested CUR data across restarts; unset keeps it in memory
+	curStore, err := cur.NewStore(os.Getenv("CUR_STORE_PATH"))
+	if err != nil {
+		return nil, err
+	}
+
+	// BUDGETS_PATH persists budgets as JSON; unset keeps them in memory
+	budgetStore, err := budget.NewStore(os.Getenv("BUDGETS_PATH"))
+	if err != nil {
+		return nil, err
+	}
+
+	// CHARGEBACK_RULES_PATH persists chargeback rules as JSON; unset keeps them in memory
+	chargebackStore, err := chargeback.NewStore(os.Getenv("CHARGEBACK_RULES_PATH"))
+	if err != nil {
+		return nil, err
+	}
+
+	// CURRENCY_RATES_FILE loads a local rates table; CURRENCY_RATES_URL fetches daily rates
+	var currencyRates currency.Provider
+	if path := os.Getenv("CURRENCY_RATES_FILE"); path != "" {
+		table, err := currency.LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		currencyRates = table
+	} else if url := os.Getenv("CURRENCY_RATES_URL"); url != "" {
+		currencyRates = &currency.HTTPProvider{URLTemplate: url}
+	}
+
+	costSource := os.Getenv("COST_SOURCE")
+	if costSource == "" {
+		costSource = "cost_explorer"
+	}
+	if costSource != "cost_explorer" && costSource != "cur" {
+		return nil, fmt.Errorf("invalid COST_SOURCE %q, expected cost_explorer or cur", costSource)
+	}
+
 	cfg := &Config{
-		AWSConfig: awsCfg,
+		AWSConfig:        awsCfg,
+		APIKeys:          apiKeys,
+		CURDataDir:       os.Getenv("CUR_DATA_DIR"),
+		CURS3Bucket:      os.Getenv("CUR_S3_BUCKET"),
+		CURS3Prefix:      os.Getenv("CUR_S3_PREFIX"),
+		CURS3Endpoint:    os.Getenv("CUR_S3_ENDPOINT"),
+		CURStore:         curStore,
+		CostSource:       costSource,
+		BudgetStore:      budgetStore,
+		BudgetWebhookURL: os.Getenv("BUDGET_ALERT_WEBHOOK_URL"),
+		ChargebackStore:  chargebackStore,
+		CurrencyRates:    currencyRates,
 	}
 
 	return cfg, nil
-}
\ No newline at end of file
+}
+
+// parseAPIKeys parses a comma-separated list of key:role pairs.
+func parseAPIKeys(raw string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, role, ok := strings.Cut(entry, ":")
+		if !ok || key == "" || role == "" {
+			return nil, fmt.Errorf("invalid DEVCOST_API_KEYS entry, expected key:role")
+		}
+		keys[key] = role
+	}
+	return keys, nil
+}
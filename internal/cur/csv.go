@@ -0,0 +1,67 @@
+package cur
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// ReadCSV parses CUR line items from a CSV stream with a header row.
+func ReadCSV(r io.Reader) ([]LineItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CUR header: %v", err)
+	}
+
+	fields := make(map[string]int)
+	tagColumns := make(map[int]string)
+	for i, name := range header {
+		if key, ok := tagColumnKey(name); ok {
+			tagColumns[i] = key
+			continue
+		}
+		if field, ok := columnAliases[normalizeColumnName(name)]; ok {
+			fields[field] = i
+		}
+	}
+	if _, ok := fields["unblended_cost"]; !ok {
+		return nil, fmt.Errorf("CUR file has no unblended cost column")
+	}
+
+	items := []LineItem{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CUR line %d: %v", line, err)
+		}
+
+		get := func(field string) string {
+			i, ok := fields[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+
+		tags := make(map[string]string)
+		for i, key := range tagColumns {
+			if i < len(record) && record[i] != "" {
+				tags[key] = record[i]
+			}
+		}
+
+		item, err := newLineItem(get, tags)
+		if err != nil {
+			return nil, fmt.Errorf("CUR line %d: %v", line, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,92 @@
+package cur
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{
+			name: "CUR 2.0",
+			csv: "line_item_usage_start_date,line_item_product_code,line_item_resource_id,line_item_unblended_cost,resource_tags\n" +
+				`2025-05-01T00:00:00Z,AmazonEC2,i-0abc,1.5,"{""user_project"":""dev-cluster""}"` + "\n" +
+				`2025-05-02T00:00:00Z,AmazonS3,my-bucket,0.25,"{""user_project"":""dev-cluster""}"` + "\n" +
+				`2025-05-02T00:00:00Z,AmazonEC2,i-0def,3,"{}"` + "\n",
+		},
+		{
+			name: "legacy CUR",
+			csv: "lineItem/UsageStartDate,lineItem/ProductCode,lineItem/ResourceId,lineItem/UnblendedCost,resourceTags/user:project\n" +
+				"2025-05-01T00:00:00Z,AmazonEC2,i-0abc,1.5,dev-cluster\n" +
+				"2025-05-02T00:00:00Z,AmazonS3,my-bucket,0.25,dev-cluster\n" +
+				"2025-05-02T00:00:00Z,AmazonEC2,i-0def,3,\n",
+		},
+	}
+
+	start := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 5, 8, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		items, err := ReadCSV(strings.NewReader(tt.csv))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(items) != 3 {
+			t.Fatalf("%s: expected 3 line items, got %d", tt.name, len(items))
+		}
+
+		store, _ := NewStore("")
+		store.Replace("test", "1", items)
+		costs := store.ResourceCostsByTag("project", start, end, MetricUnblended)
+		if len(costs) != 1 {
+			t.Fatalf("%s: expected 1 tag value, got %d", tt.name, len(costs))
+		}
+		if got := costs["dev-cluster"][ResourceKey{Provider: "AWS", Service: "AmazonEC2", ResourceID: "i-0abc", Currency: "USD"}]; got != 1.5 {
+			t.Errorf("%s: expected i-0abc cost 1.5, got %f", tt.name, got)
+		}
+		if got := costs["dev-cluster"][ResourceKey{Provider: "AWS", Service: "AmazonS3", ResourceID: "my-bucket", Currency: "USD"}]; got != 0.25 {
+			t.Errorf("%s: expected my-bucket cost 0.25, got %f", tt.name, got)
+		}
+	}
+}
+
+func TestAmortizedCost(t *testing.T) {
+	csv := "line_item_usage_start_date,line_item_line_item_type,line_item_resource_id,line_item_unblended_cost," +
+		"savings_plan_savings_plan_effective_cost,savings_plan_total_commitment_to_date,savings_plan_used_commitment,resource_tags\n" +
+		`2025-05-01T00:00:00Z,SavingsPlanCoveredUsage,i-0abc,1.0,0.6,,,"{""user_project"":""dev-cluster""}"` + "\n" +
+		`2025-05-01T00:00:00Z,SavingsPlanNegation,i-0abc,-1.0,,,,"{""user_project"":""dev-cluster""}"` + "\n" +
+		`2025-05-01T00:00:00Z,SavingsPlanRecurringFee,,1.0,,1.0,0.6,"{}"` + "\n"
+
+	items, err := ReadCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []float64{0.6, 0, 0.4}
+	for i, item := range items {
+		if diff := item.AmortizedCost - want[i]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s: expected amortized cost %f, got %f", item.LineItemType, want[i], item.AmortizedCost)
+		}
+	}
+}
+
+func TestResourceCostsByTagKeepsCurrenciesApart(t *testing.T) {
+	usageStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	tags := map[string]string{"project": "dev-cluster"}
+	store, _ := NewStore("")
+	store.Replace("cur", "1", []LineItem{{Provider: "AWS", UsageStart: usageStart, ProductCode: "AmazonEC2", ResourceID: "vm", UnblendedCost: 2, Tags: tags}})
+	store.Replace("focus/azure.csv", "1", []LineItem{{Provider: "Microsoft", UsageStart: usageStart, ProductCode: "Virtual Machines", ResourceID: "vm", UnblendedCost: 3, Currency: "EUR", Tags: tags}})
+
+	costs := store.ResourceCostsByTag("project", usageStart, usageStart.AddDate(0, 0, 1), MetricUnblended)
+	totals := make(map[string]float64)
+	for key, cost := range costs["dev-cluster"] {
+		totals[key.Currency] += cost
+	}
+	if len(totals) != 2 || totals["USD"] != 2 || totals["EUR"] != 3 {
+		t.Errorf("expected 2 USD and 3 EUR, got %v", totals)
+	}
+}
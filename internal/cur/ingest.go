@@ -0,0 +1,86 @@
+package cur
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// IngestResult reports what an ingestion run did for one source.
+type IngestResult struct {
+	Source        string `json:"source"`
+	FilesScanned  int    `json:"files_scanned"`
+	FilesIngested int    `json:"files_ingested"`
+	FilesSkipped  int    `json:"files_skipped"`
+	LineItems     int    `json:"line_items"`
+}
+
+// Ingest loads new or changed CUR files from src into store. Files already ingested at the
+// same version are skipped, and files no longer present in src are removed from the store.
+// Changes are not persisted; callers Save the store after the run.
+func Ingest(ctx context.Context, store *Store, src Source) (IngestResult, error) {
+	result := IngestResult{Source: src.Prefix()}
+
+	objects, err := src.List(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	keep := make(map[string]bool)
+	for _, obj := range objects {
+		result.FilesScanned++
+		keep[obj.Key] = true
+		if store.HasVersion(obj.Key, obj.Version) {
+			result.FilesSkipped++
+			continue
+		}
+
+		items, err := readObject(ctx, src, obj)
+		if err != nil {
+			log.Printf("Failed to ingest CUR file %s: %v", obj.Key, err)
+			return result, fmt.Errorf("failed to ingest %s: %v", obj.Key, err)
+		}
+		store.Replace(obj.Key, obj.Version, items)
+		result.FilesIngested++
+		result.LineItems += len(items)
+		log.Printf("Ingested %d CUR line items from %s", len(items), obj.Key)
+	}
+
+	store.Prune(src.Prefix(), keep)
+	return result, nil
+}
+
+// readObject opens a CUR file and parses it according to its extension.
+func readObject(ctx context.Context, src Source, obj Object) ([]LineItem, error) {
+	f, err := src.Open(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadFile(obj.Key, f, obj.Size)
+}
+
+// ReadFile parses a CUR or FOCUS file (.parquet, .csv or .csv.gz) according to the extension of name.
+func ReadFile(name string, r io.ReaderAt, size int64) ([]LineItem, error) {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, ".parquet") {
+		return ReadParquet(r, size)
+	}
+	if !strings.HasSuffix(name, ".csv") && !strings.HasSuffix(name, ".csv.gz") {
+		return nil, fmt.Errorf("unsupported file type %s, expected .parquet, .csv or .csv.gz", name)
+	}
+
+	var reader io.Reader = io.NewSectionReader(r, 0, size)
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	return ReadCSV(reader)
+}
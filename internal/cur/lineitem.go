@@ -0,0 +1,62 @@
+package cur
+
+import (
+	"strings"
+	"time"
+)
+
+// LineItem is a normalised Cost and Usage Report line item. FOCUS rows from other
+// clouds are normalised into the same shape, with Provider set accordingly.
+type LineItem struct {
+	Provider         string
+	BillingAccountID string
+	UsageStart       time.Time
+	UsageEnd         time.Time
+	AccountID        string
+	ProductCode      string
+	ServiceName      string
+	Region           string
+	ResourceID       string
+	UsageType        string
+	LineItemType     string
+	UnblendedCost    float64
+	AmortizedCost    float64
+	Currency         string
+	Tags             map[string]string
+}
+
+// Tag returns the value of a cost allocation tag on the line item.
+// CUR exports tag keys as "user:project" (legacy) or "user_project" (CUR 2.0),
+// so all spellings of the key are checked.
+func (li LineItem) Tag(key string) (string, bool) {
+	return lookupTag(li.Tags, key)
+}
+
+// lookupTag finds a tag value under any of the spellings a CUR export may use.
+func lookupTag(tags map[string]string, key string) (string, bool) {
+	for _, candidate := range tagKeyCandidates(key) {
+		if value, ok := tags[candidate]; ok && value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// tagKeyCandidates returns the spellings a tag key may have in a CUR export.
+func tagKeyCandidates(key string) []string {
+	normalized := normalizeColumnName(key)
+	return []string{key, "user:" + key, "user_" + normalized, normalized}
+}
+
+// normalizeColumnName lowercases a name and replaces separators with underscores.
+func normalizeColumnName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package cur
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// columnAliases maps normalised CUR 2.0, legacy CUR and FOCUS column names to line item fields.
+var columnAliases = map[string]string{
+	"line_item_usage_start_date":                                  "usage_start",
+	"lineitem_usagestartdate":                                     "usage_start",
+	"line_item_usage_end_date":                                    "usage_end",
+	"lineitem_usageenddate":                                       "usage_end",
+	"line_item_usage_account_id":                                  "account_id",
+	"lineitem_usageaccountid":                                     "account_id",
+	"line_item_product_code":                                      "product_code",
+	"lineitem_productcode":                                        "product_code",
+	"line_item_resource_id":                                       "resource_id",
+	"lineitem_resourceid":                                         "resource_id",
+	"line_item_usage_type":                                        "usage_type",
+	"lineitem_usagetype":                                          "usage_type",
+	"line_item_line_item_type":                                    "line_item_type",
+	"lineitem_lineitemtype":                                       "line_item_type",
+	"line_item_unblended_cost":                                    "unblended_cost",
+	"lineitem_unblendedcost":                                      "unblended_cost",
+	"line_item_currency_code":                                     "currency",
+	"lineitem_currencycode":                                       "currency",
+	"savings_plan_savings_plan_effective_cost":                    "sp_effective_cost",
+	"savingsplan_savingsplaneffectivecost":                        "sp_effective_cost",
+	"savings_plan_total_commitment_to_date":                       "sp_total_commitment",
+	"savingsplan_totalcommitmenttodate":                           "sp_total_commitment",
+	"savings_plan_used_commitment":                                "sp_used_commitment",
+	"savingsplan_usedcommitment":                                  "sp_used_commitment",
+	"reservation_effective_cost":                                  "ri_effective_cost",
+	"reservation_effectivecost":                                   "ri_effective_cost",
+	"reservation_unused_amortized_upfront_fee_for_billing_period": "ri_unused_upfront_fee",
+	"reservation_unusedamortizedupfrontfeeforbillingperiod":       "ri_unused_upfront_fee",
+	"reservation_unused_recurring_fee":                            "ri_unused_recurring_fee",
+	"reservation_unusedrecurringfee":                              "ri_unused_recurring_fee",
+	"reservation_reservation_a_r_n":                               "ri_arn",
+	"reservation_reservationarn":                                  "ri_arn",
+	"resource_tags":                                               "resource_tags",
+	"bill_payer_account_id":                                       "billing_account_id",
+	"bill_payeraccountid":                                         "billing_account_id",
+	"product_product_name":                                        "service_name",
+	"product_productname":                                         "service_name",
+	"product_region_code":                                         "region",
+	"product_regioncode":                                          "region",
+	// FinOps FOCUS 1.x columns
+	"providername":      "provider",
+	"billingaccountid":  "billing_account_id",
+	"chargeperiodstart": "usage_start",
+	"chargeperiodend":   "usage_end",
+	"subaccountid":      "account_id",
+	"servicename":       "service_name",
+	"regionid":          "region",
+	"resourceid":        "resource_id",
+	"chargecategory":    "line_item_type",
+	"billedcost":        "unblended_cost",
+	"effectivecost":     "effective_cost",
+	"billingcurrency":   "currency",
+	"tags":              "resource_tags",
+}
+
+// defaultProvider is the provider of CUR line items, which carry no provider column.
+const defaultProvider = "AWS"
+
+// legacyTagPrefixes prefix per-tag columns, e.g. "resourceTags/user:project" in legacy CSV
+// exports and "resource_tags_user_project" in legacy Parquet exports.
+var legacyTagPrefixes = []string{"resourcetags/", "resource_tags_"}
+
+// tagColumnKey returns the tag key for a per-tag column, or false if name is not a tag column.
+func tagColumnKey(name string) (string, bool) {
+	for _, prefix := range legacyTagPrefixes {
+		if len(name) > len(prefix) && strings.HasPrefix(strings.ToLower(name), prefix) {
+			return name[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+// newLineItem builds a line item from raw column values, keyed by line item field.
+func newLineItem(get func(field string) string, tags map[string]string) (LineItem, error) {
+	var err error
+	item := LineItem{
+		Provider:         get("provider"),
+		BillingAccountID: get("billing_account_id"),
+		AccountID:        get("account_id"),
+		ProductCode:      get("product_code"),
+		ServiceName:      get("service_name"),
+		Region:           get("region"),
+		ResourceID:       get("resource_id"),
+		UsageType:        get("usage_type"),
+		LineItemType:     get("line_item_type"),
+		Currency:         get("currency"),
+		Tags:             tags,
+	}
+	if raw := get("resource_tags"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &item.Tags); err != nil {
+			return item, fmt.Errorf("invalid tags: %v", err)
+		}
+	}
+	if item.Provider == "" {
+		item.Provider = defaultProvider
+	}
+	if item.ProductCode == "" {
+		item.ProductCode = item.ServiceName
+	}
+	if item.UsageStart, err = parseTimestamp(get("usage_start")); err != nil {
+		return item, fmt.Errorf("invalid usage start date: %v", err)
+	}
+	if item.UsageEnd, err = parseTimestamp(get("usage_end")); err != nil {
+		return item, fmt.Errorf("invalid usage end date: %v", err)
+	}
+	if item.UnblendedCost, err = parseAmount(get("unblended_cost")); err != nil {
+		return item, fmt.Errorf("invalid unblended cost: %v", err)
+	}
+	if item.AmortizedCost, err = amortizedCost(item, get); err != nil {
+		return item, fmt.Errorf("invalid amortization column: %v", err)
+	}
+	return item, nil
+}
+
+// amortizedCost spreads Savings Plans and Reserved Instance commitments over the usage
+// they cover, following the AWS amortized cost definition for each line item type.
+func amortizedCost(item LineItem, get func(field string) string) (float64, error) {
+	amount := func(fields ...string) (float64, error) {
+		total := 0.0
+		for _, field := range fields {
+			value, err := parseAmount(get(field))
+			if err != nil {
+				return 0, err
+			}
+			total += value
+		}
+		return total, nil
+	}
+
+	// FOCUS data already carries the amortized cost as EffectiveCost
+	if get("effective_cost") != "" {
+		return amount("effective_cost")
+	}
+
+	switch item.LineItemType {
+	case "SavingsPlanCoveredUsage":
+		return amount("sp_effective_cost")
+	case "SavingsPlanRecurringFee":
+		// Only the unused part of the commitment remains as a cost of its own
+		total, err := amount("sp_total_commitment")
+		if err != nil {
+			return 0, err
+		}
+		used, err := amount("sp_used_commitment")
+		if err != nil {
+			return 0, err
+		}
+		return total - used, nil
+	case "SavingsPlanNegation", "SavingsPlanUpfrontFee":
+		return 0, nil
+	case "DiscountedUsage":
+		return amount("ri_effective_cost")
+	case "RIFee":
+		return amount("ri_unused_upfront_fee", "ri_unused_recurring_fee")
+	case "Fee":
+		// Upfront RI fees are amortized into DiscountedUsage
+		if get("ri_arn") != "" {
+			return 0, nil
+		}
+	}
+	return item.UnblendedCost, nil
+}
+
+// parseTimestamp parses the timestamp formats used by CUR exports.
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised timestamp %q", value)
+}
+
+// parseAmount parses a cost column, treating empty values as zero.
+func parseAmount(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
@@ -0,0 +1,159 @@
+package cur
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// julianDayUnixEpoch is the Julian day number of 1970-01-01, used by INT96 timestamps.
+const julianDayUnixEpoch = 2440588
+
+// parquetColumn describes how a Parquet leaf column maps onto a line item.
+type parquetColumn struct {
+	field   string // line item field for scalar columns
+	tagKey  string // tag key for legacy per-tag columns
+	mapPart string // "key" or "value" for the CUR 2.0 resource_tags or FOCUS Tags map
+	convert func(parquet.Value) string
+}
+
+// ReadParquet parses CUR line items from a Parquet file of the given size.
+func ReadParquet(r io.ReaderAt, size int64) ([]LineItem, error) {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CUR Parquet file: %v", err)
+	}
+
+	schema := file.Schema()
+	columns := make([]parquetColumn, len(schema.Columns()))
+	hasCost := false
+	for i, path := range schema.Columns() {
+		leaf, _ := schema.Lookup(path...)
+		column := parquetColumn{convert: parquetConverter(leaf.Node.Type())}
+		switch {
+		case len(path) == 3 && columnAliases[normalizeColumnName(path[0])] == "resource_tags":
+			column.mapPart = path[2]
+		case len(path) == 1:
+			if key, ok := tagColumnKey(path[0]); ok {
+				column.tagKey = key
+			} else {
+				column.field = columnAliases[normalizeColumnName(path[0])]
+			}
+		}
+		if column.field == "unblended_cost" {
+			hasCost = true
+		}
+		columns[i] = column
+	}
+	if !hasCost {
+		return nil, fmt.Errorf("CUR file has no unblended cost column")
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	items := []LineItem{}
+	rows := make([]parquet.Row, 256)
+	for {
+		n, readErr := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			values := make(map[string]string)
+			tags := make(map[string]string)
+			var tagKeys, tagValues []string
+
+			for _, value := range row {
+				column := columns[value.Column()]
+				switch {
+				case column.mapPart == "key":
+					tagKeys = append(tagKeys, column.convert(value))
+				case column.mapPart == "value":
+					tagValues = append(tagValues, column.convert(value))
+				case column.tagKey != "":
+					if s := column.convert(value); s != "" {
+						tags[column.tagKey] = s
+					}
+				case column.field != "":
+					values[column.field] = column.convert(value)
+				}
+			}
+			for i, key := range tagKeys {
+				if key != "" && i < len(tagValues) {
+					tags[key] = tagValues[i]
+				}
+			}
+
+			item, err := newLineItem(func(field string) string { return values[field] }, tags)
+			if err != nil {
+				return nil, fmt.Errorf("CUR Parquet row %d: %v", len(items)+1, err)
+			}
+			items = append(items, item)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read CUR Parquet rows: %v", readErr)
+		}
+	}
+
+	return items, nil
+}
+
+// parquetConverter returns a function that renders values of the given Parquet type as strings
+// in the same formats CSV exports use.
+func parquetConverter(t parquet.Type) func(parquet.Value) string {
+	logical := t.LogicalType()
+	return func(v parquet.Value) string {
+		if v.IsNull() {
+			return ""
+		}
+		switch v.Kind() {
+		case parquet.Boolean:
+			return strconv.FormatBool(v.Boolean())
+		case parquet.Int32:
+			if logical != nil && logical.Date != nil {
+				return time.Unix(int64(v.Int32())*86400, 0).UTC().Format("2006-01-02")
+			}
+			if logical != nil && logical.Decimal != nil {
+				return strconv.FormatFloat(float64(v.Int32())/math.Pow10(int(logical.Decimal.Scale)), 'f', -1, 64)
+			}
+			return strconv.FormatInt(int64(v.Int32()), 10)
+		case parquet.Int64:
+			if logical != nil && logical.Timestamp != nil {
+				return timestampFromUnit(v.Int64(), logical.Timestamp.Unit).Format(time.RFC3339)
+			}
+			if logical != nil && logical.Decimal != nil {
+				return strconv.FormatFloat(float64(v.Int64())/math.Pow10(int(logical.Decimal.Scale)), 'f', -1, 64)
+			}
+			return strconv.FormatInt(v.Int64(), 10)
+		case parquet.Int96:
+			i96 := v.Int96()
+			nanos := int64(i96[1])<<32 | int64(i96[0])
+			days := int64(i96[2]) - julianDayUnixEpoch
+			return time.Unix(days*86400, nanos).UTC().Format(time.RFC3339)
+		case parquet.Float:
+			return strconv.FormatFloat(float64(v.Float()), 'f', -1, 32)
+		case parquet.Double:
+			return strconv.FormatFloat(v.Double(), 'f', -1, 64)
+		default:
+			return string(v.ByteArray())
+		}
+	}
+}
+
+// timestampFromUnit converts a Parquet INT64 timestamp in the given unit to a time.
+func timestampFromUnit(value int64, unit format.TimeUnit) time.Time {
+	switch {
+	case unit.Micros != nil:
+		return time.UnixMicro(value).UTC()
+	case unit.Nanos != nil:
+		return time.Unix(0, value).UTC()
+	default:
+		return time.UnixMilli(value).UTC()
+	}
+}
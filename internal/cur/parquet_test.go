@@ -0,0 +1,48 @@
+package cur
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestReadParquet(t *testing.T) {
+	type row struct {
+		UsageStart   time.Time         `parquet:"line_item_usage_start_date,timestamp(millisecond)"`
+		ProductCode  string            `parquet:"line_item_product_code"`
+		ResourceID   string            `parquet:"line_item_resource_id"`
+		Cost         float64           `parquet:"line_item_unblended_cost"`
+		ResourceTags map[string]string `parquet:"resource_tags"`
+	}
+
+	usageStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, []row{
+		{UsageStart: usageStart, ProductCode: "AmazonEC2", ResourceID: "i-0abc", Cost: 1.5, ResourceTags: map[string]string{"user_project": "dev-cluster"}},
+		{UsageStart: usageStart, ProductCode: "AmazonS3", ResourceID: "my-bucket", Cost: 0.25, ResourceTags: map[string]string{}},
+	}); err != nil {
+		t.Fatalf("failed to write Parquet fixture: %v", err)
+	}
+
+	items, err := ReadParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(items))
+	}
+	if !items[0].UsageStart.Equal(usageStart) {
+		t.Errorf("expected usage start %s, got %s", usageStart, items[0].UsageStart)
+	}
+	if items[0].ResourceID != "i-0abc" || items[0].UnblendedCost != 1.5 {
+		t.Errorf("unexpected first line item: %+v", items[0])
+	}
+	if value, ok := items[0].Tag("project"); !ok || value != "dev-cluster" {
+		t.Errorf("expected project tag dev-cluster, got %q", value)
+	}
+	if _, ok := items[1].Tag("project"); ok {
+		t.Errorf("expected second line item to be untagged")
+	}
+}
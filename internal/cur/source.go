@@ -0,0 +1,153 @@
+package cur
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Object is a CUR file available from a Source.
+type Object struct {
+	Key     string
+	Version string
+	Size    int64
+}
+
+// File is an opened CUR file.
+type File interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// Source lists and opens CUR files from a location such as a directory or S3 bucket.
+type Source interface {
+	// Prefix is common to the keys of every object the source lists.
+	Prefix() string
+	List(ctx context.Context) ([]Object, error)
+	Open(ctx context.Context, obj Object) (File, error)
+}
+
+// isCURFile reports whether a file name has a supported CUR data extension.
+func isCURFile(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".parquet") || strings.HasSuffix(name, ".csv") || strings.HasSuffix(name, ".csv.gz")
+}
+
+// DirSource reads CUR files from a local directory tree.
+type DirSource struct {
+	Dir string
+}
+
+func (d DirSource) Prefix() string {
+	abs, err := filepath.Abs(d.Dir)
+	if err != nil {
+		abs = d.Dir
+	}
+	return "file://" + filepath.ToSlash(abs) + "/"
+}
+
+func (d DirSource) List(ctx context.Context) ([]Object, error) {
+	objects := []Object{}
+	err := filepath.WalkDir(d.Dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isCURFile(entry.Name()) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(d.Dir, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{
+			Key:     d.Prefix() + filepath.ToSlash(rel),
+			Version: info.ModTime().UTC().Format("20060102T150405.000000000") + "-" + strconv.FormatInt(info.Size(), 10),
+			Size:    info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CUR directory %s: %v", d.Dir, err)
+	}
+	return objects, nil
+}
+
+func (d DirSource) Open(ctx context.Context, obj Object) (File, error) {
+	rel := strings.TrimPrefix(obj.Key, d.Prefix())
+	return os.Open(filepath.Join(d.Dir, filepath.FromSlash(rel)))
+}
+
+// S3Source reads CUR files from an S3 bucket, or an S3-compatible stand-in.
+type S3Source struct {
+	Client    *s3.Client
+	Bucket    string
+	KeyPrefix string
+}
+
+func (b S3Source) Prefix() string {
+	return "s3://" + b.Bucket + "/" + b.KeyPrefix
+}
+
+func (b S3Source) List(ctx context.Context) ([]Object, error) {
+	objects := []Object{}
+	paginator := s3.NewListObjectsV2Paginator(b.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.Bucket),
+		Prefix: aws.String(b.KeyPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list CUR objects in s3://%s/%s: %v", b.Bucket, b.KeyPrefix, err)
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if !isCURFile(key) {
+				continue
+			}
+			objects = append(objects, Object{
+				Key:     "s3://" + b.Bucket + "/" + key,
+				Version: aws.ToString(obj.ETag),
+				Size:    aws.ToInt64(obj.Size),
+			})
+		}
+	}
+	return objects, nil
+}
+
+func (b S3Source) Open(ctx context.Context, obj Object) (File, error) {
+	key := strings.TrimPrefix(obj.Key, "s3://"+b.Bucket+"/")
+	result, err := b.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", obj.Key, err)
+	}
+	defer result.Body.Close()
+
+	// Parquet needs random access, so the object is buffered in memory
+	data, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", obj.Key, err)
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+// nopCloser adds a no-op Close to an in-memory reader.
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
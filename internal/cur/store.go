@@ -0,0 +1,294 @@
+package cur
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CostMetric selects which cost column a query aggregates.
+type CostMetric string
+
+const (
+	MetricUnblended CostMetric = "unblended"
+	MetricAmortized CostMetric = "amortized"
+	// MetricNetAmortized is amortized cost after discounts; only Cost Explorer provides it.
+	MetricNetAmortized CostMetric = "net_amortized"
+)
+
+// ResourceKey identifies a resource within a service, and the currency its costs are in.
+type ResourceKey struct {
+	Provider   string
+	Service    string
+	ResourceID string
+	Currency   string
+}
+
+// partition holds the line items of one ingested CUR file in columnar form.
+type partition struct {
+	Source           string
+	Version          string
+	IngestedAt       time.Time
+	Provider         []string
+	BillingAccountID []string
+	UsageStart       []int64
+	UsageEnd         []int64
+	AccountID        []string
+	ProductCode      []string
+	ServiceName      []string
+	Region           []string
+	ResourceID       []string
+	UsageType        []string
+	LineItemType     []string
+	Currency         []string
+	UnblendedCost    []float64
+	AmortizedCost    []float64
+	Tags             []map[string]string
+}
+
+// newPartition converts line items to columnar form.
+func newPartition(source, version string, items []LineItem) *partition {
+	p := &partition{Source: source, Version: version, IngestedAt: time.Now().UTC()}
+	for _, item := range items {
+		p.Provider = append(p.Provider, item.Provider)
+		p.BillingAccountID = append(p.BillingAccountID, item.BillingAccountID)
+		p.UsageStart = append(p.UsageStart, item.UsageStart.Unix())
+		p.UsageEnd = append(p.UsageEnd, item.UsageEnd.Unix())
+		p.AccountID = append(p.AccountID, item.AccountID)
+		p.ProductCode = append(p.ProductCode, item.ProductCode)
+		p.ServiceName = append(p.ServiceName, item.ServiceName)
+		p.Region = append(p.Region, item.Region)
+		p.ResourceID = append(p.ResourceID, item.ResourceID)
+		p.UsageType = append(p.UsageType, item.UsageType)
+		p.LineItemType = append(p.LineItemType, item.LineItemType)
+		p.Currency = append(p.Currency, item.Currency)
+		p.UnblendedCost = append(p.UnblendedCost, item.UnblendedCost)
+		p.AmortizedCost = append(p.AmortizedCost, item.AmortizedCost)
+		p.Tags = append(p.Tags, item.Tags)
+	}
+	return p
+}
+
+// lineItem reassembles row i of the partition.
+func (p *partition) lineItem(i int) LineItem {
+	return LineItem{
+		Provider:         p.Provider[i],
+		BillingAccountID: p.BillingAccountID[i],
+		UsageStart:       time.Unix(p.UsageStart[i], 0).UTC(),
+		UsageEnd:         time.Unix(p.UsageEnd[i], 0).UTC(),
+		AccountID:        p.AccountID[i],
+		ProductCode:      p.ProductCode[i],
+		ServiceName:      p.ServiceName[i],
+		Region:           p.Region[i],
+		ResourceID:       p.ResourceID[i],
+		UsageType:        p.UsageType[i],
+		LineItemType:     p.LineItemType[i],
+		UnblendedCost:    p.UnblendedCost[i],
+		AmortizedCost:    p.AmortizedCost[i],
+		Currency:         p.Currency[i],
+		Tags:             p.Tags[i],
+	}
+}
+
+// Store is a local columnar store of CUR line items, partitioned by source file.
+// When created with a path, changes are persisted to disk by Save.
+type Store struct {
+	mu         sync.RWMutex
+	path       string
+	partitions map[string]*partition
+	dirty      bool // changed since the last Save
+}
+
+// StoreStats summarises the contents of a Store.
+type StoreStats struct {
+	Files      int       `json:"files"`
+	LineItems  int       `json:"line_items"`
+	UsageStart time.Time `json:"usage_start,omitempty"`
+	UsageEnd   time.Time `json:"usage_end,omitempty"`
+}
+
+// NewStore creates a store, loading previously persisted data from path if it exists.
+// An empty path keeps the store in memory only.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, partitions: make(map[string]*partition)}
+	if path == "" {
+		return s, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CUR store %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(&s.partitions); err != nil {
+		return nil, fmt.Errorf("failed to load CUR store %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// Has reports whether source has been ingested at any version.
+func (s *Store) Has(source string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.partitions[source]
+	return ok
+}
+
+// HasVersion reports whether source has already been ingested at the given version.
+func (s *Store) HasVersion(source, version string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.partitions[source]
+	return ok && p.Version == version
+}
+
+// Replace stores the line items of source, replacing any previously ingested version.
+// The change is kept in memory until Save.
+func (s *Store) Replace(source, version string, items []LineItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partitions[source] = newPartition(source, version, items)
+	s.dirty = true
+}
+
+// Prune removes partitions under prefix whose source is not in keep, so files
+// deleted or overwritten upstream stop contributing costs. The change is kept in
+// memory until Save.
+func (s *Store) Prune(prefix string, keep map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for source := range s.partitions {
+		if strings.HasPrefix(source, prefix) && !keep[source] {
+			delete(s.partitions, source)
+			s.dirty = true
+		}
+	}
+}
+
+// Save persists the store to disk if it changed since it was loaded or last saved.
+// Callers save once after a batch of changes, as every save rewrites the whole store.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// Len returns the number of stored line items.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, p := range s.partitions {
+		n += len(p.UsageStart)
+	}
+	return n
+}
+
+// Stats summarises the stored line items.
+func (s *Store) Stats() StoreStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := StoreStats{Files: len(s.partitions)}
+	for _, p := range s.partitions {
+		stats.LineItems += len(p.UsageStart)
+		for i := range p.UsageStart {
+			start, end := time.Unix(p.UsageStart[i], 0).UTC(), time.Unix(p.UsageEnd[i], 0).UTC()
+			if stats.UsageStart.IsZero() || start.Before(stats.UsageStart) {
+				stats.UsageStart = start
+			}
+			if end.After(stats.UsageEnd) {
+				stats.UsageEnd = end
+			}
+		}
+	}
+	return stats
+}
+
+// Scan calls fn for every line item whose usage started in [start, end).
+func (s *Store) Scan(start, end time.Time, fn func(LineItem)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	from, to := start.Unix(), end.Unix()
+	for _, p := range s.partitions {
+		for i, usageStart := range p.UsageStart {
+			if usageStart < from || usageStart >= to {
+				continue
+			}
+			fn(p.lineItem(i))
+		}
+	}
+}
+
+// ResourceCostsByTag sums cost per resource for each value of tagKey over line items
+// whose usage started in [start, end). Untagged line items are skipped. Costs in different
+// currencies, e.g. imported FOCUS data in EUR, are kept apart by the key's Currency; line
+// items without one are in USD.
+func (s *Store) ResourceCostsByTag(tagKey string, start, end time.Time, metric CostMetric) map[string]map[ResourceKey]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	costs := make(map[string]map[ResourceKey]float64)
+	from, to := start.Unix(), end.Unix()
+	for _, p := range s.partitions {
+		column := p.UnblendedCost
+		if metric == MetricAmortized {
+			column = p.AmortizedCost
+		}
+		for i, usageStart := range p.UsageStart {
+			if usageStart < from || usageStart >= to {
+				continue
+			}
+			tagValue, ok := lookupTag(p.Tags[i], tagKey)
+			if !ok {
+				continue
+			}
+			if costs[tagValue] == nil {
+				costs[tagValue] = make(map[ResourceKey]float64)
+			}
+			currency := p.Currency[i]
+			if currency == "" {
+				currency = "USD"
+			}
+			costs[tagValue][ResourceKey{Provider: p.Provider[i], Service: p.ProductCode[i], ResourceID: p.ResourceID[i], Currency: currency}] += column[i]
+		}
+	}
+	return costs
+}
+
+// save persists the store to disk; callers must hold the write lock.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create CUR store directory: %v", err)
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to write CUR store: %v", err)
+	}
+	if err := gob.NewEncoder(f).Encode(s.partitions); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write CUR store: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write CUR store: %v", err)
+	}
+	return os.Rename(tmp, s.path)
+}
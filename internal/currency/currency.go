@@ -0,0 +1,178 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// Provider looks up the exchange rate between two currencies on a date.
+type Provider interface {
+	Rate(ctx context.Context, from, to string, date time.Time) (models.ExchangeRate, error)
+}
+
+// Table is an in-memory rates table. Each entry quotes currencies against a base on one date;
+// lookups use the latest entry on or before the requested date.
+type Table struct {
+	Source  string
+	entries []tableEntry
+}
+
+type tableEntry struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+	date  time.Time
+}
+
+// NewTable creates a table quoting rates against base on date, e.g. as a stub provider in tests.
+func NewTable(source, base string, date time.Time, rates map[string]float64) *Table {
+	t := &Table{Source: source}
+	t.Add(base, date, rates)
+	return t
+}
+
+// Add records the rates quoted against base on date.
+func (t *Table) Add(base string, date time.Time, rates map[string]float64) {
+	entry := tableEntry{Base: strings.ToUpper(base), Date: date.Format("2006-01-02"), Rates: make(map[string]float64), date: date}
+	for code, rate := range rates {
+		entry.Rates[strings.ToUpper(code)] = rate
+	}
+	t.entries = append(t.entries, entry)
+	sort.Slice(t.entries, func(i, j int) bool { return t.entries[i].date.Before(t.entries[j].date) })
+}
+
+// LoadFile reads a rates table from a JSON file holding either a single
+// {"base": "USD", "date": "2025-05-01", "rates": {"EUR": 0.92}} object or a list of them.
+func LoadFile(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read currency rates from %s: %v", path, err)
+	}
+
+	var entries []tableEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var entry tableEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse currency rates from %s: %v", path, err)
+		}
+		entries = []tableEntry{entry}
+	}
+
+	t := &Table{Source: "file:" + path}
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in currency rates from %s", entry.Date, path)
+		}
+		if entry.Base == "" {
+			return nil, fmt.Errorf("missing base currency for %s in currency rates from %s", entry.Date, path)
+		}
+		t.Add(entry.Base, date, entry.Rates)
+	}
+	return t, nil
+}
+
+// Rate returns the rate converting from into to, derived through the entry's base currency.
+func (t *Table) Rate(ctx context.Context, from, to string, date time.Time) (models.ExchangeRate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+
+	var entry *tableEntry
+	for i := range t.entries {
+		if t.entries[i].date.After(date) {
+			break
+		}
+		entry = &t.entries[i]
+	}
+	if entry == nil {
+		return models.ExchangeRate{}, fmt.Errorf("no exchange rates on or before %s", date.Format("2006-01-02"))
+	}
+
+	fromRate, ok := entry.quote(from)
+	if !ok {
+		return models.ExchangeRate{}, fmt.Errorf("no exchange rate for %s on %s", from, entry.Date)
+	}
+	toRate, ok := entry.quote(to)
+	if !ok {
+		return models.ExchangeRate{}, fmt.Errorf("no exchange rate for %s on %s", to, entry.Date)
+	}
+
+	return models.ExchangeRate{
+		From:   from,
+		To:     to,
+		Rate:   toRate / fromRate,
+		Date:   entry.Date,
+		Source: t.Source,
+	}, nil
+}
+
+// quote returns units of code per unit of the entry's base currency.
+func (e tableEntry) quote(code string) (float64, bool) {
+	if code == e.Base {
+		return 1, true
+	}
+	rate, ok := e.Rates[code]
+	return rate, ok && rate > 0
+}
+
+// ConvertTagCosts converts tag costs in place to the target currency using rates for date,
+// recording the original amount and the rate used.
+func ConvertTagCosts(ctx context.Context, p Provider, costs []models.TagCost, to string, date time.Time) error {
+	to = strings.ToUpper(to)
+	for i := range costs {
+		cost := &costs[i]
+		if strings.EqualFold(cost.Currency, to) {
+			continue
+		}
+		rate, err := p.Rate(ctx, cost.Currency, to, date)
+		if err != nil {
+			return err
+		}
+		cost.SourceCost = cost.Cost
+		cost.SourceCurrency = cost.Currency
+		cost.Cost *= rate.Rate
+		cost.Currency = to
+		cost.ExchangeRate = &rate
+		for j := range cost.Resources {
+			cost.Resources[j].Cost *= rate.Rate
+		}
+	}
+	return nil
+}
+
+// ConvertStatements converts chargeback statements in place to the target currency using rates for date.
+func ConvertStatements(ctx context.Context, p Provider, statements []models.ChargebackStatement, to string, date time.Time) error {
+	to = strings.ToUpper(to)
+	for i := range statements {
+		statement := &statements[i]
+		if strings.EqualFold(statement.Currency, to) {
+			continue
+		}
+		rate, err := p.Rate(ctx, statement.Currency, to, date)
+		if err != nil {
+			return err
+		}
+		statement.SourceCurrency = statement.Currency
+		statement.Currency = to
+		statement.ExchangeRate = &rate
+		statement.DirectCost *= rate.Rate
+		statement.SharedCost *= rate.Rate
+		statement.Credits *= rate.Rate
+		statement.Discount *= rate.Rate
+		statement.Total *= rate.Rate
+		for j := range statement.LineItems {
+			item := &statement.LineItems[j]
+			item.DirectCost *= rate.Rate
+			item.SharedCost *= rate.Rate
+			item.Credits *= rate.Rate
+			item.Total *= rate.Rate
+		}
+	}
+	return nil
+}
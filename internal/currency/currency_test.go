@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	rates := `[
+		{"base": "USD", "date": "2025-05-01", "rates": {"EUR": 0.90, "INR": 84}},
+		{"base": "USD", "date": "2025-05-15", "rates": {"EUR": 0.88, "INR": 85}}
+	]`
+	if err := os.WriteFile(path, []byte(rates), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The latest rate on or before the date is used, and cross rates go through the base
+	rate, err := table.Rate(context.Background(), "EUR", "INR", time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate.Date != "2025-05-15" || math.Abs(rate.Rate-85/0.88) > 1e-9 {
+		t.Errorf("expected the 2025-05-15 EUR/INR cross rate, got %+v", rate)
+	}
+
+	if _, err := table.Rate(context.Background(), "USD", "EUR", time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("expected an error for a date before the first rates")
+	}
+}
+
+func TestConvertTagCosts(t *testing.T) {
+	stub := NewTable("stub", "USD", time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), map[string]float64{"EUR": 0.5})
+	costs := []models.TagCost{
+		{TagValue: "dev-cluster", Cost: 10, Currency: "USD", Resources: []models.ResourceCost{{ResourceID: "i-0abc", Cost: 4}}},
+		{TagValue: "prod", Cost: 3, Currency: "EUR"},
+	}
+
+	if err := ConvertTagCosts(context.Background(), stub, costs, "eur", time.Date(2025, 5, 8, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if costs[0].Cost != 5 || costs[0].Resources[0].Cost != 2 || costs[0].Currency != "EUR" {
+		t.Errorf("expected USD costs halved into EUR, got %+v", costs[0])
+	}
+	if costs[0].SourceCost != 10 || costs[0].SourceCurrency != "USD" || costs[0].ExchangeRate == nil || costs[0].ExchangeRate.Date != "2025-05-01" {
+		t.Errorf("expected source amount and rate to be recorded, got %+v", costs[0])
+	}
+	if costs[1].Cost != 3 || costs[1].ExchangeRate != nil {
+		t.Errorf("expected costs already in EUR to be left alone, got %+v", costs[1])
+	}
+}
@@ -0,0 +1,82 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/models"
+)
+
+// HTTPProvider fetches daily rates from an ECB-style API such as Frankfurter, which answers
+// {"base": "EUR", "date": "2025-05-02", "rates": {"USD": 1.13}}. URLTemplate contains a
+// {date} placeholder, e.g. https://api.frankfurter.app/{date}. Responses are cached per date.
+type HTTPProvider struct {
+	URLTemplate string
+	Client      *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*Table
+}
+
+// Rate returns the rate converting from into to on date, as published by the provider.
+func (p *HTTPProvider) Rate(ctx context.Context, from, to string, date time.Time) (models.ExchangeRate, error) {
+	day := date.Format("2006-01-02")
+
+	p.mu.Lock()
+	table, ok := p.cache[day]
+	p.mu.Unlock()
+	if !ok {
+		var err error
+		table, err = p.fetch(ctx, day)
+		if err != nil {
+			return models.ExchangeRate{}, err
+		}
+		p.mu.Lock()
+		if p.cache == nil {
+			p.cache = make(map[string]*Table)
+		}
+		p.cache[day] = table
+		p.mu.Unlock()
+	}
+
+	// Providers publish on business days only and answer with the latest earlier date
+	return table.Rate(ctx, from, to, date)
+}
+
+// fetch downloads the rates published for a day.
+func (p *HTTPProvider) fetch(ctx context.Context, day string) (*Table, error) {
+	url := strings.ReplaceAll(p.URLTemplate, "{date}", day)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate provider returned status %d", resp.StatusCode)
+	}
+
+	var entry tableEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %v", err)
+	}
+	date, err := time.Parse("2006-01-02", entry.Date)
+	if err != nil || entry.Base == "" {
+		return nil, fmt.Errorf("exchange rate provider returned an invalid response for %s", day)
+	}
+
+	return NewTable(url, entry.Base, date, entry.Rates), nil
+}
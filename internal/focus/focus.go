@@ -0,0 +1,172 @@
+package focus
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+)
+
+// Columns lists the FinOps FOCUS 1.x columns emitted by exports, in order.
+var Columns = []string{
+	"BillingAccountId",
+	"BillingCurrency",
+	"BillingPeriodStart",
+	"BillingPeriodEnd",
+	"ChargePeriodStart",
+	"ChargePeriodEnd",
+	"ChargeCategory",
+	"BilledCost",
+	"EffectiveCost",
+	"ProviderName",
+	"PublisherName",
+	"InvoiceIssuerName",
+	"ServiceName",
+	"SubAccountId",
+	"RegionId",
+	"ResourceId",
+	"Tags",
+}
+
+// Row is one FOCUS cost record.
+type Row struct {
+	BillingAccountID   string            `json:"BillingAccountId"`
+	BillingCurrency    string            `json:"BillingCurrency"`
+	BillingPeriodStart time.Time         `json:"BillingPeriodStart"`
+	BillingPeriodEnd   time.Time         `json:"BillingPeriodEnd"`
+	ChargePeriodStart  time.Time         `json:"ChargePeriodStart"`
+	ChargePeriodEnd    time.Time         `json:"ChargePeriodEnd"`
+	ChargeCategory     string            `json:"ChargeCategory"`
+	BilledCost         float64           `json:"BilledCost"`
+	EffectiveCost      float64           `json:"EffectiveCost"`
+	ProviderName       string            `json:"ProviderName"`
+	PublisherName      string            `json:"PublisherName"`
+	InvoiceIssuerName  string            `json:"InvoiceIssuerName"`
+	ServiceName        string            `json:"ServiceName"`
+	SubAccountID       string            `json:"SubAccountId"`
+	RegionID           string            `json:"RegionId"`
+	ResourceID         string            `json:"ResourceId"`
+	Tags               map[string]string `json:"Tags"`
+}
+
+// chargeCategories maps CUR line item types to FOCUS charge categories.
+var chargeCategories = map[string]string{
+	"Usage":                   "Usage",
+	"DiscountedUsage":         "Usage",
+	"SavingsPlanCoveredUsage": "Usage",
+	"SavingsPlanNegation":     "Usage",
+	"Fee":                     "Purchase",
+	"RIFee":                   "Purchase",
+	"SavingsPlanRecurringFee": "Purchase",
+	"SavingsPlanUpfrontFee":   "Purchase",
+	"Tax":                     "Tax",
+	"Credit":                  "Credit",
+	"Refund":                  "Adjustment",
+	"BundledDiscount":         "Credit",
+	"EdpDiscount":             "Credit",
+	"PrivateRateDiscount":     "Credit",
+	// Cost Explorer RECORD_TYPE spellings
+	"Recurring reservation fee":            "Purchase",
+	"Upfront reservation fee":              "Purchase",
+	"Enterprise Discount Program Discount": "Credit",
+	// FOCUS categories pass through unchanged for imported data
+	"Purchase":   "Purchase",
+	"Adjustment": "Adjustment",
+}
+
+// ChargeCategory maps a CUR line item type or Cost Explorer record type to a FOCUS charge category.
+func ChargeCategory(lineItemType string) string {
+	if category, ok := chargeCategories[lineItemType]; ok {
+		return category
+	}
+	return "Usage"
+}
+
+// BillingPeriod returns the calendar month containing t, which is the AWS billing period.
+func BillingPeriod(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// FromLineItem converts a normalised line item to a FOCUS row.
+func FromLineItem(item cur.LineItem) Row {
+	periodStart, periodEnd := BillingPeriod(item.UsageStart)
+	serviceName := item.ServiceName
+	if serviceName == "" {
+		serviceName = item.ProductCode
+	}
+	return Row{
+		BillingAccountID:   item.BillingAccountID,
+		BillingCurrency:    item.Currency,
+		BillingPeriodStart: periodStart,
+		BillingPeriodEnd:   periodEnd,
+		ChargePeriodStart:  item.UsageStart,
+		ChargePeriodEnd:    item.UsageEnd,
+		ChargeCategory:     ChargeCategory(item.LineItemType),
+		BilledCost:         item.UnblendedCost,
+		EffectiveCost:      item.AmortizedCost,
+		ProviderName:       item.Provider,
+		PublisherName:      item.Provider,
+		InvoiceIssuerName:  item.Provider,
+		ServiceName:        serviceName,
+		SubAccountID:       item.AccountID,
+		RegionID:           item.Region,
+		ResourceID:         item.ResourceID,
+		Tags:               item.Tags,
+	}
+}
+
+// WriteCSV writes rows as FOCUS CSV with a header row. Tags are encoded as a JSON object.
+func WriteCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		tags := row.Tags
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+		record := []string{
+			row.BillingAccountID,
+			row.BillingCurrency,
+			formatTime(row.BillingPeriodStart),
+			formatTime(row.BillingPeriodEnd),
+			formatTime(row.ChargePeriodStart),
+			formatTime(row.ChargePeriodEnd),
+			row.ChargeCategory,
+			strconv.FormatFloat(row.BilledCost, 'f', -1, 64),
+			strconv.FormatFloat(row.EffectiveCost, 'f', -1, 64),
+			row.ProviderName,
+			row.PublisherName,
+			row.InvoiceIssuerName,
+			row.ServiceName,
+			row.SubAccountID,
+			row.RegionID,
+			row.ResourceID,
+			string(tagsJSON),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatTime renders a FOCUS datetime, leaving zero times empty.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,56 @@
+package focus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/deepanshumishra/devcost-api/internal/cur"
+)
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	chargeStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows := []Row{
+		{
+			BillingCurrency:   "EUR",
+			ChargePeriodStart: chargeStart,
+			ChargePeriodEnd:   chargeStart.AddDate(0, 0, 1),
+			ChargeCategory:    "Usage",
+			BilledCost:        12.5,
+			EffectiveCost:     10,
+			ProviderName:      "Microsoft",
+			ServiceName:       "Virtual Machines",
+			SubAccountID:      "sub-123",
+			ResourceID:        "/subscriptions/sub-123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1",
+			Tags:              map[string]string{"project": "dev-cluster"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Exported FOCUS files can be imported back as line items
+	items, err := cur.ReadCSV(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading FOCUS CSV: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 line item, got %d", len(items))
+	}
+
+	item := items[0]
+	if item.Provider != "Microsoft" || item.Currency != "EUR" || item.AccountID != "sub-123" {
+		t.Errorf("unexpected line item: %+v", item)
+	}
+	if item.UnblendedCost != 12.5 || item.AmortizedCost != 10 {
+		t.Errorf("expected billed 12.5 and effective 10, got %f and %f", item.UnblendedCost, item.AmortizedCost)
+	}
+	if !item.UsageStart.Equal(chargeStart) {
+		t.Errorf("expected charge period start %s, got %s", chargeStart, item.UsageStart)
+	}
+	if value, ok := item.Tag("project"); !ok || value != "dev-cluster" {
+		t.Errorf("expected project tag dev-cluster, got %q", value)
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+type Budget struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Scope        BudgetScope       `json:"scope"`
+	MonthlyLimit float64           `json:"monthly_limit"`
+	Currency     string            `json:"currency"`
+	Thresholds   []BudgetThreshold `json:"thresholds"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	// FiredAlerts records alerts already sent, keyed by month and threshold, so each fires once per month.
+	FiredAlerts map[string]time.Time `json:"fired_alerts,omitempty"`
+}
+
+type BudgetScope struct {
+	Type  string `json:"type"`          // "tag", "account" or "service"
+	Key   string `json:"key,omitempty"` // tag key, for tag scopes
+	Value string `json:"value"`
+}
+
+type BudgetThreshold struct {
+	Percent float64 `json:"percent"`
+	Type    string  `json:"type"` // "actual" or "forecast"
+}
+
+type BudgetStatus struct {
+	Budget          Budget        `json:"budget"`
+	Month           string        `json:"month"`
+	ActualSpend     float64       `json:"actual_spend"`
+	ForecastedSpend float64       `json:"forecasted_spend"`
+	ActualPercent   float64       `json:"actual_percent"`
+	ForecastPercent float64       `json:"forecast_percent"`
+	Alerts          []BudgetAlert `json:"alerts"`
+	// Set when spend was converted from the billing currency to the budget's currency
+	SourceCurrency string        `json:"source_currency,omitempty"`
+	ExchangeRate   *ExchangeRate `json:"exchange_rate,omitempty"`
+}
+
+type BudgetAlert struct {
+	BudgetID  string    `json:"budget_id"`
+	Budget    string    `json:"budget"`
+	Month     string    `json:"month"`
+	Threshold float64   `json:"threshold"`
+	Type      string    `json:"type"`
+	Spend     float64   `json:"spend"`
+	Limit     float64   `json:"limit"`
+	Currency  string    `json:"currency"`
+	FiredAt   time.Time `json:"fired_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+type ChargebackRules struct {
+	TagKey      string           `json:"tag_key"`
+	CostCenters []CostCenter     `json:"cost_centers"`
+	SharedCosts []SharedCostRule `json:"shared_costs"`
+}
+
+type CostCenter struct {
+	Name      string   `json:"name"`
+	TagValues []string `json:"tag_values"`
+	// DiscountPercent is an internal discount applied to the cost center's statement total.
+	DiscountPercent float64 `json:"discount_percent,omitempty"`
+}
+
+type SharedCostRule struct {
+	Name     string             `json:"name"`
+	Services []string           `json:"services,omitempty"` // e.g. "AWS Support (Business)", "AWS Data Transfer"
+	Untagged bool               `json:"untagged,omitempty"` // costs without a mapped tag value
+	Method   string             `json:"method"`             // "proportional" or "fixed"
+	Ratios   map[string]float64 `json:"ratios,omitempty"`   // cost center name -> ratio, for fixed splits
+}
+
+type ChargebackStatement struct {
+	CostCenter string               `json:"cost_center"`
+	Month      string               `json:"month"`
+	Currency   string               `json:"currency"`
+	TagValues  []string             `json:"tag_values"`
+	LineItems  []ChargebackLineItem `json:"line_items"`
+	DirectCost float64              `json:"direct_cost"`
+	SharedCost float64              `json:"shared_cost"`
+	Credits    float64              `json:"credits"`
+	Discount   float64              `json:"discount"`
+	Total      float64              `json:"total"`
+	// Set when amounts were converted from the billing currency
+	SourceCurrency string        `json:"source_currency,omitempty"`
+	ExchangeRate   *ExchangeRate `json:"exchange_rate,omitempty"`
+}
+
+type ChargebackLineItem struct {
+	Service    string  `json:"service"`
+	DirectCost float64 `json:"direct_cost"`
+	SharedCost float64 `json:"shared_cost"`
+	Credits    float64 `json:"credits"`
+	Total      float64 `json:"total"`
+}
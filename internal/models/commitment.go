@@ -0,0 +1,46 @@
+package models
+
+type ReservationUtilization struct {
+	Start                  string  `json:"start"`
+	End                    string  `json:"end"`
+	UtilizationPercentage  float64 `json:"utilization_percentage"`
+	PurchasedHours         float64 `json:"purchased_hours"`
+	UsedHours              float64 `json:"used_hours"`
+	UnusedHours            float64 `json:"unused_hours"`
+	AmortizedFee           float64 `json:"amortized_fee"`
+	UnusedHoursCost        float64 `json:"unused_hours_cost"`
+	OnDemandCostEquivalent float64 `json:"on_demand_cost_equivalent"`
+	NetSavings             float64 `json:"net_savings"`
+}
+
+type SavingsPlansUtilization struct {
+	Start                  string  `json:"start"`
+	End                    string  `json:"end"`
+	UtilizationPercentage  float64 `json:"utilization_percentage"`
+	TotalCommitment        float64 `json:"total_commitment"`
+	UsedCommitment         float64 `json:"used_commitment"`
+	UnusedCommitment       float64 `json:"unused_commitment"`
+	AmortizedCommitment    float64 `json:"amortized_commitment"`
+	OnDemandCostEquivalent float64 `json:"on_demand_cost_equivalent"`
+	NetSavings             float64 `json:"net_savings"`
+}
+
+type ReservationCoverage struct {
+	Start                   string  `json:"start"`
+	End                     string  `json:"end"`
+	CoverageHoursPercentage float64 `json:"coverage_hours_percentage"`
+	ReservedHours           float64 `json:"reserved_hours"`
+	OnDemandHours           float64 `json:"on_demand_hours"`
+	TotalRunningHours       float64 `json:"total_running_hours"`
+	OnDemandCost            float64 `json:"on_demand_cost"`
+}
+
+type SavingsPlansCoverage struct {
+	Start              string            `json:"start"`
+	End                string            `json:"end"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
+	CoveragePercentage float64           `json:"coverage_percentage"`
+	SpendCovered       float64           `json:"spend_covered"`
+	OnDemandCost       float64           `json:"on_demand_cost"`
+	TotalCost          float64           `json:"total_cost"`
+}
@@ -1,16 +1,65 @@
 package models
 
 type TagCost struct {
-	TagKey      string          `json:"tag_key"`
-	TagValue    string          `json:"tag_value"`
-	Cost        float64         `json:"cost"`
-	Currency    string          `json:"currency"`
-	Resources   []ResourceCost  `json:"resources"`
-	CreatorName string          `json:"creator_name,omitempty"` // Add for aws:createdBy
+	TagKey      string         `json:"tag_key"`
+	TagValue    string         `json:"tag_value"`
+	Cost        float64        `json:"cost"`
+	Currency    string         `json:"currency"`
+	Resources   []ResourceCost `json:"resources"`
+	CreatorName string         `json:"creator_name,omitempty"` // Add for aws:createdBy
+	// Set when costs were converted from the billing currency
+	SourceCost     float64       `json:"source_cost,omitempty"`
+	SourceCurrency string        `json:"source_currency,omitempty"`
+	ExchangeRate   *ExchangeRate `json:"exchange_rate,omitempty"`
+}
+
+type ExchangeRate struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Rate   float64 `json:"rate"`
+	Date   string  `json:"date"`
+	Source string  `json:"source"`
 }
 
 type ResourceCost struct {
+	Provider     string  `json:"provider,omitempty"`
 	ResourceType string  `json:"resource_type"`
 	ResourceID   string  `json:"resource_id"`
 	Cost         float64 `json:"cost"`
-}
\ No newline at end of file
+}
+
+type CostAllocationTag struct {
+	TagKey          string `json:"tag_key"`
+	Type            string `json:"type"`
+	Status          string `json:"status"`
+	LastUpdatedDate string `json:"last_updated_date,omitempty"`
+	LastUsedDate    string `json:"last_used_date,omitempty"`
+}
+
+type CostAllocationTagStatusUpdate struct {
+	TagKey string `json:"tag_key"`
+	Status string `json:"status"`
+}
+
+type CostAllocationTagUpdateError struct {
+	TagKey  string `json:"tag_key"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type CostAllocationTagBackfill struct {
+	BackfillFrom  string `json:"backfill_from"`
+	Status        string `json:"status"`
+	RequestedAt   string `json:"requested_at,omitempty"`
+	CompletedAt   string `json:"completed_at,omitempty"`
+	LastUpdatedAt string `json:"last_updated_at,omitempty"`
+}
+
+// BedrockModelUsage is a model's Bedrock token consumption over a period, from CloudWatch.
+type BedrockModelUsage struct {
+	ModelID       string  `json:"model_id"`
+	InputTokens   int64   `json:"input_tokens"`
+	OutputTokens  int64   `json:"output_tokens"`
+	Invocations   int64   `json:"invocations"`
+	EstimatedCost float64 `json:"estimated_cost,omitempty"` // on-demand list price, USD
+}
@@ -0,0 +1,11 @@
+package models
+
+type Optimization struct {
+	Category                 string  `json:"category"` // e.g. "modernization"
+	ResourceType             string  `json:"resource_type"`
+	ResourceID               string  `json:"resource_id"`
+	CurrentConfiguration     string  `json:"current_configuration"`
+	RecommendedConfiguration string  `json:"recommended_configuration"`
+	Reason                   string  `json:"reason"`
+	EstimatedMonthlySavings  float64 `json:"estimated_monthly_savings"` // USD
+}
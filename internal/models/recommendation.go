@@ -0,0 +1,65 @@
+package models
+
+type CommitmentRecommendation struct {
+	CommitmentType             string  `json:"commitment_type"` // "savings_plans" or "reservation"
+	Offering                   string  `json:"offering"`        // Savings Plans type or reserved service
+	Term                       string  `json:"term"`
+	PaymentOption              string  `json:"payment_option"`
+	LookbackPeriod             string  `json:"lookback_period"`
+	AccountID                  string  `json:"account_id,omitempty"`
+	InstanceType               string  `json:"instance_type,omitempty"`
+	Region                     string  `json:"region,omitempty"`
+	HourlyCommitment           float64 `json:"hourly_commitment,omitempty"`
+	Quantity                   float64 `json:"quantity,omitempty"`
+	UpfrontCost                float64 `json:"upfront_cost"`
+	MonthlyRecurringCost       float64 `json:"monthly_recurring_cost,omitempty"`
+	EstimatedMonthlySavings    float64 `json:"estimated_monthly_savings"`
+	EstimatedSavingsPercentage float64 `json:"estimated_savings_percentage"`
+	EstimatedUtilization       float64 `json:"estimated_utilization"`
+	Currency                   string  `json:"currency"`
+}
+
+type CommitmentSimulation struct {
+	Start                   string  `json:"start"`
+	End                     string  `json:"end"`
+	Service                 string  `json:"service,omitempty"`
+	Hours                   int     `json:"hours"`
+	HourlyCommitment        float64 `json:"hourly_commitment"`
+	Term                    string  `json:"term"`
+	DiscountRate            float64 `json:"discount_rate"`
+	OnDemandCost            float64 `json:"on_demand_cost"`
+	CommitmentCost          float64 `json:"commitment_cost"`
+	CoveredOnDemandCost     float64 `json:"covered_on_demand_cost"`
+	UncoveredOnDemandCost   float64 `json:"uncovered_on_demand_cost"`
+	TotalCostWithCommitment float64 `json:"total_cost_with_commitment"`
+	EstimatedSavings        float64 `json:"estimated_savings"`
+	SavingsPercentage       float64 `json:"savings_percentage"`
+	Utilization             float64 `json:"utilization"`
+	Coverage                float64 `json:"coverage"`
+	Currency                string  `json:"currency"`
+}
+
+type RightsizingRecommendation struct {
+	ResourceType            string                 `json:"resource_type"`
+	ResourceID              string                 `json:"resource_id"`
+	Region                  string                 `json:"region"`
+	CurrentType             string                 `json:"current_type"`
+	RecommendedType         string                 `json:"recommended_type"`
+	CPUP95                  float64                `json:"cpu_p95"`
+	CPUMax                  float64                `json:"cpu_max"`
+	MemoryP95               *float64               `json:"memory_p95,omitempty"`
+	NetworkP95Mbps          float64                `json:"network_p95_mbps"`
+	CurrentHourlyPrice      float64                `json:"current_hourly_price"`
+	RecommendedHourlyPrice  float64                `json:"recommended_hourly_price"`
+	EstimatedMonthlySavings float64                `json:"estimated_monthly_savings"`
+	Currency                string                 `json:"currency"`
+	Confidence              string                 `json:"confidence"`
+	Reason                  string                 `json:"reason"`
+	ComputeOptimizer        *ComputeOptimizerCheck `json:"compute_optimizer,omitempty"`
+}
+
+type ComputeOptimizerCheck struct {
+	Finding         string `json:"finding"`
+	RecommendedType string `json:"recommended_type,omitempty"`
+	Agrees          bool   `json:"agrees"`
+}
@@ -7,7 +7,8 @@ type Resource struct {
 }
 
 type UnusedResource struct {
-	ResourceType string `json:"resource_type"`
-	ResourceID   string `json:"resource_id"`
-	Reason       string `json:"reason"`
+	ResourceType         string  `json:"resource_type"`
+	ResourceID           string  `json:"resource_id"`
+	Reason               string  `json:"reason"`
+	EstimatedMonthlyCost float64 `json:"estimated_monthly_cost,omitempty"` // USD, at us-east-1 list prices
 }
\ No newline at end of file
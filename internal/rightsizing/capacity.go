@@ -0,0 +1,80 @@
+package rightsizing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// OffHoursShare is the share of a week's hours outside business hours.
+const OffHoursShare = 108.0 / 168.0
+
+// CapacityRecommendation is the outcome of sizing an Auto Scaling group's minimum capacity.
+type CapacityRecommendation struct {
+	MinSize         int32
+	OffHoursMinSize int32 // equal to MinSize when a schedule would not help
+	PeakInstances   int32
+	Reason          string
+}
+
+// OffHours reports whether an hour falls outside business hours, 08:00 to 20:00 UTC on weekdays.
+func OffHours(t time.Time) bool {
+	t = t.UTC()
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return true
+	}
+	return t.Hour() < 8 || t.Hour() >= 20
+}
+
+// InstancesNeeded is how many instances keep a group's aggregate CPU under TargetCPUPercent, where
+// busyInstances is the sum of the instances' CPU utilization divided by 100.
+func InstancesNeeded(busyInstances float64) int32 {
+	return int32(math.Max(1, math.Ceil(busyInstances*100/TargetCPUPercent)))
+}
+
+// RecommendCapacity recommends a minimum size for an Auto Scaling group from hourly aggregate
+// CPU, as busy instances by hour. Groups with scaling policies can drop to the instances needed
+// in quiet hours (p10) and scale out for the rest; groups without them have to cover their peak,
+// but can scale in outside business hours on a schedule. It returns false when there is too
+// little data or the current minimum is already right.
+func RecommendCapacity(busyInstances map[time.Time]float64, currentMin int32, hasScalingPolicies bool) (CapacityRecommendation, bool) {
+	if len(busyInstances) < MinDataPoints {
+		return CapacityRecommendation{}, false
+	}
+
+	needed := make([]float64, 0, len(busyInstances))
+	businessPeak, offHoursPeak := int32(0), int32(0)
+	for hour, busy := range busyInstances {
+		instances := InstancesNeeded(busy)
+		needed = append(needed, float64(instances))
+		if OffHours(hour) {
+			offHoursPeak = max(offHoursPeak, instances)
+		} else {
+			businessPeak = max(businessPeak, instances)
+		}
+	}
+	peak := max(businessPeak, offHoursPeak)
+	recommendation := CapacityRecommendation{PeakInstances: peak}
+
+	if hasScalingPolicies {
+		recommendation.MinSize = int32(Percentile(needed, 10))
+		recommendation.OffHoursMinSize = recommendation.MinSize
+		recommendation.Reason = fmt.Sprintf("Scaling policies cover the peak of %d instances; %d cover quiet hours", peak, recommendation.MinSize)
+	} else {
+		recommendation.MinSize = peak
+		recommendation.OffHoursMinSize = peak
+		recommendation.Reason = fmt.Sprintf("No scaling policies; the peak needs %d instances", peak)
+		if businessPeak > 0 && offHoursPeak < businessPeak {
+			recommendation.MinSize = businessPeak
+			recommendation.OffHoursMinSize = offHoursPeak
+			recommendation.Reason = fmt.Sprintf("No scaling policies; business hours need %d instances, nights and weekends %d", businessPeak, offHoursPeak)
+		}
+	}
+
+	if recommendation.MinSize >= currentMin && recommendation.OffHoursMinSize >= currentMin {
+		return CapacityRecommendation{}, false
+	}
+	// A schedule can only lower capacity below the new minimum
+	recommendation.MinSize = min(recommendation.MinSize, currentMin)
+	return recommendation, true
+}
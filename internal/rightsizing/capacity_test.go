@@ -0,0 +1,53 @@
+package rightsizing
+
+import (
+	"testing"
+	"time"
+)
+
+// weekOfHours returns a week of hourly samples starting on a Monday, with the given busy
+// instances in business hours and off hours.
+func weekOfHours(business, offHours float64) map[time.Time]float64 {
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	hours := make(map[time.Time]float64)
+	for i := 0; i < 168; i++ {
+		hour := start.Add(time.Duration(i) * time.Hour)
+		hours[hour] = business
+		if OffHours(hour) {
+			hours[hour] = offHours
+		}
+	}
+	return hours
+}
+
+func TestRecommendCapacity(t *testing.T) {
+	tests := []struct {
+		name            string
+		busy            map[time.Time]float64
+		currentMin      int32
+		policies        bool
+		ok              bool
+		minSize         int32
+		offHoursMinSize int32
+	}{
+		// 2.1 busy instances need 3 at 70%, 0.5 need 1
+		{"schedule without policies", weekOfHours(2.1, 0.5), 6, false, true, 3, 1},
+		{"flat load without policies", weekOfHours(2.1, 2.1), 6, false, true, 3, 3},
+		{"policies drop to quiet hours", weekOfHours(2.1, 0.5), 6, true, true, 1, 1},
+		{"minimum already right", weekOfHours(2.1, 2.1), 3, false, false, 0, 0},
+		{"schedule below current minimum", weekOfHours(2.1, 0.5), 3, false, true, 3, 1},
+		{"too little data", map[time.Time]float64{time.Now(): 0.1}, 6, false, false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RecommendCapacity(tt.busy, tt.currentMin, tt.policies)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if got.MinSize != tt.minSize || got.OffHoursMinSize != tt.offHoursMinSize {
+				t.Errorf("got min %d, off-hours min %d, want %d and %d", got.MinSize, got.OffHoursMinSize, tt.minSize, tt.offHoursMinSize)
+			}
+		})
+	}
+}
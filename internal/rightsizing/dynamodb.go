@@ -0,0 +1,28 @@
+package rightsizing
+
+import "math"
+
+// DynamoDBTargetUtilization is the share of provisioned capacity, in percent, that DynamoDB auto
+// scaling keeps consumed by default.
+const DynamoDBTargetUtilization = 70.0
+
+// CapacityUnitsNeeded is how many provisioned read or write capacity units keep consumption, in
+// units per second, under DynamoDBTargetUtilization.
+func CapacityUnitsNeeded(consumedPerSecond float64) int64 {
+	return int64(math.Max(1, math.Ceil(consumedPerSecond*100/DynamoDBTargetUtilization)))
+}
+
+// ProvisionedUnits returns the capacity units needed at the peak hour and in quiet hours (p10)
+// from hourly average consumption in units per second. Hourly averages smooth out bursts within
+// the hour, which burst capacity and auto scaling absorb. It returns false when there is too
+// little data.
+func ProvisionedUnits(consumedPerSecond []float64) (peak, quiet int64, ok bool) {
+	if len(consumedPerSecond) < MinDataPoints {
+		return 0, 0, false
+	}
+	peakConsumed := 0.0
+	for _, consumed := range consumedPerSecond {
+		peakConsumed = math.Max(peakConsumed, consumed)
+	}
+	return CapacityUnitsNeeded(peakConsumed), CapacityUnitsNeeded(Percentile(consumedPerSecond, 10)), true
+}
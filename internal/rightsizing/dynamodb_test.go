@@ -0,0 +1,39 @@
+package rightsizing
+
+import "testing"
+
+func TestProvisionedUnits(t *testing.T) {
+	hours := func(quiet, busy float64) []float64 {
+		values := make([]float64, 0, 168)
+		for i := 0; i < 168; i++ {
+			values = append(values, quiet)
+			if i%24 >= 8 && i%24 < 20 {
+				values[i] = busy
+			}
+		}
+		return values
+	}
+
+	tests := []struct {
+		name     string
+		consumed []float64
+		ok       bool
+		peak     int64
+		quiet    int64
+	}{
+		// 35 units per second need 50 at 70%, 7 need 10
+		{"daily cycle", hours(7, 35), true, 50, 10},
+		{"flat load", hours(35, 35), true, 50, 50},
+		{"no consumption keeps one unit", hours(0, 0), true, 1, 1},
+		{"too little data", []float64{35}, false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peak, quiet, ok := ProvisionedUnits(tt.consumed)
+			if ok != tt.ok || peak != tt.peak || quiet != tt.quiet {
+				t.Errorf("ProvisionedUnits() = %d, %d, %v; want %d, %d, %v", peak, quiet, ok, tt.peak, tt.quiet, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package rightsizing
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// successorFamilies maps previous-generation families to the current generation they migrate to
+// without architecture changes.
+var successorFamilies = map[string]string{
+	"t1": "t3",
+	"t2": "t3",
+	"m1": "m5",
+	"m3": "m5",
+	"m4": "m5",
+	"c1": "c5",
+	"c3": "c5",
+	"c4": "c5",
+	"r3": "r5",
+	"r4": "r5",
+	"i2": "i3",
+	"d2": "d3",
+}
+
+// gravitonFamilies maps x86 families to the Graviton (arm64) family of the same class.
+var gravitonFamilies = map[string]string{
+	"t3":  "t4g",
+	"t3a": "t4g",
+	"m5":  "m6g",
+	"m5a": "m6g",
+	"m6i": "m7g",
+	"m6a": "m7g",
+	"m7i": "m7g",
+	"c5":  "c6g",
+	"c5a": "c6g",
+	"c6i": "c7g",
+	"c6a": "c7g",
+	"c7i": "c7g",
+	"r5":  "r6g",
+	"r5a": "r6g",
+	"r6i": "r7g",
+	"r6a": "r7g",
+	"r7i": "r7g",
+}
+
+// gravitonSizes are the sizes of the m6g/c6g/r6g and m7g/c7g/r7g families.
+var gravitonSizes = []string{"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "metal"}
+
+// burstableSizes are the sizes of the t3 and t4g families.
+var burstableSizes = []string{"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"}
+
+// familySizes lists the sizes each target family is offered in, smallest first.
+var familySizes = map[string][]string{
+	"t3":  burstableSizes,
+	"t4g": burstableSizes,
+	"m5":  {"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "24xlarge", "metal"},
+	"c5":  {"large", "xlarge", "2xlarge", "4xlarge", "9xlarge", "12xlarge", "18xlarge", "24xlarge", "metal"},
+	"r5":  {"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "24xlarge", "metal"},
+	"i3":  {"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "16xlarge", "metal"},
+	"d3":  {"xlarge", "2xlarge", "4xlarge", "8xlarge"},
+	"m6g": gravitonSizes,
+	"c6g": gravitonSizes,
+	"r6g": gravitonSizes,
+	"m7g": gravitonSizes,
+	"c7g": gravitonSizes,
+	"r7g": gravitonSizes,
+}
+
+// SuccessorType returns the current-generation equivalent of a previous-generation instance type
+// or RDS instance class, e.g. m4.large -> m5.large, db.r4.xlarge -> db.r5.xlarge. Sizes the
+// successor lacks map to its next larger size, e.g. m3.medium -> m5.large, so capacity is never cut.
+func SuccessorType(instanceType string) (string, bool) {
+	return mapFamily(instanceType, successorFamilies, false)
+}
+
+// GravitonType returns the Graviton equivalent of an x86 instance type or RDS instance class,
+// e.g. m5.large -> m6g.large. Sizes the Graviton family lacks, e.g. m5.24xlarge, c5.9xlarge or
+// bare metal, have no equivalent.
+func GravitonType(instanceType string) (string, bool) {
+	return mapFamily(instanceType, gravitonFamilies, true)
+}
+
+// mapFamily replaces the family of an instance type using families, keeping any "db." prefix and
+// the size. Unless exact is set, sizes the target family lacks map to its next larger size.
+func mapFamily(instanceType string, families map[string]string, exact bool) (string, bool) {
+	prefix := ""
+	if strings.HasPrefix(instanceType, "db.") {
+		prefix, instanceType = "db.", strings.TrimPrefix(instanceType, "db.")
+	}
+	family, size := SplitType(instanceType)
+	target, ok := families[family]
+	if !ok || size == "" {
+		return "", false
+	}
+	size, ok = matchSize(size, familySizes[target], prefix == "db.", strings.HasPrefix(target, "t"), exact)
+	if !ok {
+		return "", false
+	}
+	return prefix + target + "." + size, true
+}
+
+// matchSize returns the smallest size among sizes with at least the capacity of size, or with
+// exactly its capacity when exact is set. Bare metal only maps to bare metal, and never exactly,
+// since metal sizes differ between families. RDS offers no nano or metal classes, and no medium
+// outside burstable families.
+func matchSize(size string, sizes []string, rds, burstable, exact bool) (string, bool) {
+	want, ok := sizeUnits(size)
+	if !ok || (exact && size == "metal") {
+		return "", false
+	}
+	best, bestUnits := "", math.Inf(1)
+	for _, candidate := range sizes {
+		if rds && (candidate == "nano" || candidate == "metal" || (candidate == "medium" && !burstable)) {
+			continue
+		}
+		if (size == "metal") != (candidate == "metal") {
+			continue
+		}
+		units, _ := sizeUnits(candidate)
+		if units < want || (exact && units != want) {
+			continue
+		}
+		if units < bestUnits {
+			best, bestUnits = candidate, units
+		}
+	}
+	return best, best != ""
+}
+
+// sizeUnits converts a size name to relative capacity, where large is 2 and Nxlarge is 4N. Bare
+// metal has no fixed capacity and counts as 0.
+func sizeUnits(size string) (float64, bool) {
+	switch size {
+	case "nano":
+		return 0.125, true
+	case "micro":
+		return 0.25, true
+	case "small":
+		return 0.5, true
+	case "medium":
+		return 1, true
+	case "large":
+		return 2, true
+	case "xlarge":
+		return 4, true
+	case "metal":
+		return 0, true
+	}
+	multiple, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge"))
+	if !strings.HasSuffix(size, "xlarge") || err != nil || multiple <= 0 {
+		return 0, false
+	}
+	return float64(4 * multiple), true
+}
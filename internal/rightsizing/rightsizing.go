@@ -0,0 +1,154 @@
+package rightsizing
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Utilization targets a recommended instance must stay under at the observed p95 load.
+const (
+	TargetCPUPercent     = 70.0
+	TargetMemoryPercent  = 80.0
+	TargetNetworkPercent = 70.0
+)
+
+// MinDataPoints is the number of hourly samples (three days) needed before recommending a change.
+const MinDataPoints = 72
+
+// HoursPerMonth converts hourly prices to monthly estimates.
+const HoursPerMonth = 730
+
+// InstanceSpec describes an instance type's capacity and on-demand price.
+type InstanceSpec struct {
+	Type         string
+	VCPUs        int32
+	MemoryGiB    float64
+	NetworkGbps  float64
+	Architecture string
+	HourlyPrice  float64
+}
+
+// Utilization summarises observed load as percentiles over hourly samples.
+type Utilization struct {
+	CPUP95         float64
+	CPUMax         float64
+	MemoryP95      float64
+	HasMemory      bool
+	NetworkP95Gbps float64
+	DataPoints     int
+}
+
+// Recommendation is the outcome of sizing one instance.
+type Recommendation struct {
+	Current    InstanceSpec
+	Target     InstanceSpec
+	Confidence string
+	Reason     string
+}
+
+// familyPattern splits an instance family into its class letters, generation and suffix,
+// e.g. "m6i" into "m", "6", "i".
+var familyPattern = regexp.MustCompile(`^([a-z]+)(\d+)([a-z-]*)$`)
+
+// SplitType splits an instance type into family and size, e.g. "db.r6g.large" into "db.r6g" and "large".
+func SplitType(instanceType string) (string, string) {
+	i := strings.LastIndex(instanceType, ".")
+	if i < 0 {
+		return instanceType, ""
+	}
+	return instanceType[:i], instanceType[i+1:]
+}
+
+// CandidateFamilies returns the family itself and its general purpose, compute and memory optimised
+// siblings of the same generation and processor, e.g. m5 -> m5, c5, r5. Burstable and specialised
+// families only size within themselves.
+func CandidateFamilies(family string) []string {
+	prefix := ""
+	if strings.HasPrefix(family, "db.") {
+		prefix, family = "db.", strings.TrimPrefix(family, "db.")
+	}
+	families := []string{prefix + family}
+
+	match := familyPattern.FindStringSubmatch(family)
+	if match == nil || (match[1] != "m" && match[1] != "c" && match[1] != "r") {
+		return families
+	}
+	for _, class := range []string{"m", "c", "r"} {
+		if class != match[1] {
+			families = append(families, prefix+class+match[2]+match[3])
+		}
+	}
+	return families
+}
+
+// Percentile returns the p-th percentile (0-100) of values using nearest-rank.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Fits reports whether a candidate has the capacity for the observed load with headroom. Without
+// memory metrics, memory may shrink by at most half, as a single size step down would.
+func Fits(current InstanceSpec, usage Utilization, candidate InstanceSpec) bool {
+	if candidate.Architecture != current.Architecture {
+		return false
+	}
+	requiredVCPUs := float64(current.VCPUs) * math.Max(usage.CPUP95/TargetCPUPercent, usage.CPUMax/100)
+	requiredMemory := current.MemoryGiB / 2
+	if usage.HasMemory {
+		requiredMemory = current.MemoryGiB * usage.MemoryP95 / TargetMemoryPercent
+	}
+	requiredNetwork := usage.NetworkP95Gbps / (TargetNetworkPercent / 100)
+
+	if float64(candidate.VCPUs) < requiredVCPUs || candidate.MemoryGiB < requiredMemory {
+		return false
+	}
+	return candidate.NetworkGbps == 0 || candidate.NetworkGbps >= requiredNetwork
+}
+
+// Recommend picks the cheapest priced candidate that fits the observed load and costs less than
+// the current type. It returns false when there is too little data or the current type is
+// already right-sized.
+func Recommend(current InstanceSpec, usage Utilization, candidates []InstanceSpec) (Recommendation, bool) {
+	if usage.DataPoints < MinDataPoints || current.HourlyPrice <= 0 {
+		return Recommendation{}, false
+	}
+
+	var best *InstanceSpec
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.Type == current.Type || candidate.HourlyPrice <= 0 || candidate.HourlyPrice >= current.HourlyPrice {
+			continue
+		}
+		if !Fits(current, usage, *candidate) {
+			continue
+		}
+		if best == nil || candidate.HourlyPrice < best.HourlyPrice {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return Recommendation{}, false
+	}
+
+	confidence := "medium"
+	reason := fmt.Sprintf("CPU p95 %.1f%%", usage.CPUP95)
+	if usage.HasMemory {
+		confidence = "high"
+		reason += fmt.Sprintf(", memory p95 %.1f%%", usage.MemoryP95)
+	} else {
+		reason += ", no memory metrics (install the CloudWatch agent for higher confidence)"
+	}
+	return Recommendation{Current: current, Target: *best, Confidence: confidence, Reason: reason}, true
+}
@@ -0,0 +1,101 @@
+package rightsizing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCandidateFamilies(t *testing.T) {
+	tests := map[string][]string{
+		"m6i":    {"m6i", "c6i", "r6i"},
+		"db.r6g": {"db.r6g", "db.m6g", "db.c6g"},
+		"t3":     {"t3"},
+		"m5ad":   {"m5ad", "c5ad", "r5ad"},
+	}
+	for family, want := range tests {
+		if got := CandidateFamilies(family); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", family, want, got)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	if got := Percentile(values, 95); got != 10 {
+		t.Errorf("expected p95 10, got %f", got)
+	}
+	if got := Percentile(values, 50); got != 5 {
+		t.Errorf("expected p50 5, got %f", got)
+	}
+	if got := Percentile(nil, 95); got != 0 {
+		t.Errorf("expected 0 for no values, got %f", got)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	current := InstanceSpec{Type: "m5.2xlarge", VCPUs: 8, MemoryGiB: 32, NetworkGbps: 2.5, Architecture: "x86_64", HourlyPrice: 0.384}
+	candidates := []InstanceSpec{
+		{Type: "m5.large", VCPUs: 2, MemoryGiB: 8, NetworkGbps: 0.75, Architecture: "x86_64", HourlyPrice: 0.096},
+		{Type: "m5.xlarge", VCPUs: 4, MemoryGiB: 16, NetworkGbps: 1.25, Architecture: "x86_64", HourlyPrice: 0.192},
+		{Type: "c5.xlarge", VCPUs: 4, MemoryGiB: 8, NetworkGbps: 1.25, Architecture: "x86_64", HourlyPrice: 0.17},
+		{Type: "m6g.large", VCPUs: 2, MemoryGiB: 8, NetworkGbps: 0.75, Architecture: "arm64", HourlyPrice: 0.077},
+	}
+
+	// 20% CPU on 8 vCPUs needs ~2.3 vCPUs; 30% of 32 GiB needs 12 GiB, which rules out c5.xlarge
+	usage := Utilization{CPUP95: 20, CPUMax: 35, MemoryP95: 30, HasMemory: true, NetworkP95Gbps: 0.1, DataPoints: 168}
+	recommendation, ok := Recommend(current, usage, candidates)
+	if !ok || recommendation.Target.Type != "m5.xlarge" || recommendation.Confidence != "high" {
+		t.Fatalf("expected m5.xlarge with high confidence, got %+v (ok=%v)", recommendation, ok)
+	}
+
+	// Without memory metrics memory may only halve, so c5.xlarge is still ruled out
+	usage.HasMemory = false
+	recommendation, ok = Recommend(current, usage, candidates)
+	if !ok || recommendation.Target.Type != "m5.xlarge" || recommendation.Confidence != "medium" {
+		t.Errorf("expected m5.xlarge with medium confidence, got %+v (ok=%v)", recommendation, ok)
+	}
+
+	// Busy instances and short histories get no recommendation
+	if _, ok := Recommend(current, Utilization{CPUP95: 65, CPUMax: 90, DataPoints: 168}, candidates); ok {
+		t.Error("expected no recommendation for a busy instance")
+	}
+	if _, ok := Recommend(current, Utilization{CPUP95: 5, CPUMax: 10, DataPoints: 24}, candidates); ok {
+		t.Error("expected no recommendation with too few data points")
+	}
+}
+
+func TestModernizedTypes(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		successor    string
+		graviton     string
+	}{
+		{"m4.large", "m5.large", ""},
+		{"db.t2.micro", "db.t3.micro", ""},
+		{"m5.xlarge", "", "m6g.xlarge"},
+		{"db.r6i.2xlarge", "", "db.r7g.2xlarge"},
+		{"m6g.large", "", ""},
+		// Sizes the successor family lacks map to its next larger size
+		{"m3.medium", "m5.large", ""},
+		{"m1.small", "m5.large", ""},
+		{"c1.medium", "c5.large", ""},
+		{"c4.8xlarge", "c5.9xlarge", ""},
+		{"m4.10xlarge", "m5.12xlarge", ""},
+		{"db.m4.10xlarge", "db.m5.12xlarge", ""},
+		{"db.m5.large", "", "db.m6g.large"},
+		// Graviton types must match the size exactly
+		{"m5.24xlarge", "", ""},
+		{"c5.9xlarge", "", ""},
+		{"c5.18xlarge", "", ""},
+		{"m5.metal", "", ""},
+		{"c5.12xlarge", "", "c6g.12xlarge"},
+	}
+	for _, tt := range tests {
+		if got, _ := SuccessorType(tt.instanceType); got != tt.successor {
+			t.Errorf("%s: expected successor %q, got %q", tt.instanceType, tt.successor, got)
+		}
+		if got, _ := GravitonType(tt.instanceType); got != tt.graviton {
+			t.Errorf("%s: expected Graviton type %q, got %q", tt.instanceType, tt.graviton, got)
+		}
+	}
+}